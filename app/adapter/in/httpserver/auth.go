@@ -0,0 +1,52 @@
+package httpserver
+
+import (
+	"net/http"
+	"strings"
+
+	"go-ddd-architecture/internal/apitoken"
+)
+
+// NewAuthMiddleware 以本機 token 驗證請求（Authorization: Bearer <token>）：
+//   - 缺少或錯誤的 token 回 401 unauthorized（附 WWW-Authenticate）；
+//   - 唯讀 token 只能使用查詢方法（GET/HEAD/OPTIONS），其餘回 403 forbidden；
+//   - public 列出的路徑（例如 OpenAPI 文件）不需驗證。
+//
+// 驗證通過後將範圍放入 context，供 WebSocket 等在單一 GET 內執行命令的端點再次檢查。
+func NewAuthMiddleware(tokens apitoken.Tokens, public ...string) Middleware {
+	open := map[string]bool{}
+	for _, p := range public {
+		open[p] = true
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if open[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+			scope, ok := tokens.Lookup(bearerToken(r))
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="game"`)
+				writeJSONError(w, http.StatusUnauthorized, "unauthorized", "missing or invalid API token")
+				return
+			}
+			if !scope.CanWrite() && !safeMethod(r.Method) {
+				writeJSONError(w, http.StatusForbidden, "forbidden", "read-only token cannot "+r.Method+" "+r.URL.Path)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(apitoken.WithScope(r.Context(), scope)))
+		})
+	}
+}
+
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+func safeMethod(m string) bool {
+	return m == http.MethodGet || m == http.MethodHead || m == http.MethodOptions
+}
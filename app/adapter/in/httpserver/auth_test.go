@@ -0,0 +1,47 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-ddd-architecture/internal/apitoken"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	var gotScope apitoken.Scope
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotScope, _ = apitoken.ScopeFrom(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+	h := NewAuthMiddleware(apitoken.Tokens{Admin: "adm", Read: "ro"}, "/open")(next)
+
+	cases := []struct {
+		name, method, path, auth string
+		status                   int
+		scope                    apitoken.Scope
+	}{
+		{"missing token", http.MethodGet, "/x", "", http.StatusUnauthorized, ""},
+		{"wrong token", http.MethodGet, "/x", "Bearer nope", http.StatusUnauthorized, ""},
+		{"wrong scheme", http.MethodGet, "/x", "Basic adm", http.StatusUnauthorized, ""},
+		{"read token may GET", http.MethodGet, "/x", "Bearer ro", http.StatusNoContent, apitoken.ScopeRead},
+		{"read token may not POST", http.MethodPost, "/x", "Bearer ro", http.StatusForbidden, ""},
+		{"admin may POST", http.MethodPost, "/x", "bearer adm", http.StatusNoContent, apitoken.ScopeAdmin},
+		{"public path", http.MethodGet, "/open", "", http.StatusNoContent, ""},
+	}
+	for _, c := range cases {
+		gotScope = ""
+		req := httptest.NewRequest(c.method, c.path, nil)
+		if c.auth != "" {
+			req.Header.Set("Authorization", c.auth)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != c.status || gotScope != c.scope {
+			t.Errorf("%s: status=%d scope=%q, want %d %q", c.name, rec.Code, gotScope, c.status, c.scope)
+		}
+		if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: 401 without WWW-Authenticate", c.name)
+		}
+	}
+}
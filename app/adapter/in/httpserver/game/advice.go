@@ -0,0 +1,8 @@
+package game
+
+import "net/http"
+
+// GetAdvice 回傳進度預測（升級/購買所需時間）與依 ROI 排序的建議。
+func (h *Handler) GetAdvice(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.uc.Advice())
+}
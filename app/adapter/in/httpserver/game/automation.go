@@ -0,0 +1,52 @@
+package game
+
+import (
+	"encoding/json"
+	"net/http"
+
+	inPort "go-ddd-architecture/app/usecase/port/in/game"
+)
+
+type automationReq struct {
+	Enabled      bool  `json:"enabled"`
+	AutoPractice bool  `json:"autoPractice"`
+	AutoPlay     *bool `json:"autoPlay"`
+}
+
+// PostAutomation 設定伺服器端自動化（自動結算、自動 Practice、可選的自動遊玩）。
+func (h *Handler) PostAutomation(w http.ResponseWriter, r *http.Request) {
+	var body automationReq
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+			return
+		}
+	}
+	cmd := inPort.SetAutomation{Enabled: body.Enabled, AutoPractice: body.AutoPractice, AutoPlay: body.AutoPlay}
+	if _, err := h.uc.Dispatch(cmd); err != nil {
+		writeUsecaseError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, h.uc.GetViewModel())
+}
+
+type enqueueReq struct {
+	Type string `json:"type"`
+}
+
+// PostEnqueueTask 將任務加入佇列（目前任務結束後自動啟動）。
+func (h *Handler) PostEnqueueTask(w http.ResponseWriter, r *http.Request) {
+	var body enqueueReq
+	if r.Body != nil {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}
+	if body.Type == "" {
+		writeError(w, http.StatusBadRequest, "bad_request", "type is required")
+		return
+	}
+	if err := h.uc.EnqueueTask(body.Type); err != nil {
+		writeUsecaseError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, h.uc.GetViewModel())
+}
@@ -0,0 +1,87 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	dto "go-ddd-architecture/app/usecase/dto/game"
+	inPort "go-ddd-architecture/app/usecase/port/in/game"
+)
+
+// commandReq 為 /commands 的請求：command 為命令名稱，args 依命令而定。
+type commandReq struct {
+	Command string          `json:"command"`
+	Args    json.RawMessage `json:"args"`
+}
+
+type commandResp struct {
+	Result    dto.CommandResultDto `json:"result"`
+	ViewModel dto.ViewModelDto     `json:"viewModel"`
+}
+
+// GetAudit 回傳最近的命令稽核紀錄（最新在前）。
+func (h *Handler) GetAudit(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.uc.Audit())
+}
+
+// PostCommand 執行單一命令。
+func (h *Handler) PostCommand(w http.ResponseWriter, r *http.Request) {
+	var body commandReq
+	if r.Body == nil || json.NewDecoder(r.Body).Decode(&body) != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+		return
+	}
+	cmd, err := inPort.DecodeCommand(body.Command, body.Args)
+	if err != nil {
+		writeUsecaseError(w, err)
+		return
+	}
+	res, err := h.uc.Dispatch(cmd)
+	if err != nil {
+		writeUsecaseError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, commandResp{Result: res, ViewModel: h.uc.GetViewModel()})
+}
+
+type batchReq struct {
+	Commands []commandReq `json:"commands"`
+}
+
+// batchResp 於成功與失敗時皆回傳各步驟結果；失敗時另帶統一錯誤格式的 error 欄位。
+type batchResp struct {
+	Err       *httpError         `json:"error,omitempty"`
+	Result    dto.BatchResultDto `json:"result"`
+	ViewModel dto.ViewModelDto   `json:"viewModel"`
+}
+
+// PostCommandBatch 依序套用多個命令，全部成功才生效（單次存檔）；任一步驟失敗則整批不生效。
+func (h *Handler) PostCommandBatch(w http.ResponseWriter, r *http.Request) {
+	var body batchReq
+	if r.Body == nil || json.NewDecoder(r.Body).Decode(&body) != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+		return
+	}
+	cmds := make([]inPort.Command, len(body.Commands))
+	for i, c := range body.Commands {
+		cmd, err := inPort.DecodeCommand(c.Command, c.Args)
+		if err != nil {
+			writeUsecaseError(w, &inPort.BatchError{Index: i, Err: err})
+			return
+		}
+		cmds[i] = cmd
+	}
+	res, err := h.uc.DispatchBatch(cmds)
+	if err != nil {
+		var be *inPort.BatchError
+		if !errors.As(err, &be) {
+			writeUsecaseError(w, err)
+			return
+		}
+		status, code := errorStatus(err)
+		writeJSON(w, status, batchResp{Err: &httpError{Code: code, Message: err.Error()}, Result: res, ViewModel: h.uc.GetViewModel()})
+		return
+	}
+	writeJSON(w, http.StatusOK, batchResp{Result: res, ViewModel: h.uc.GetViewModel()})
+}
@@ -0,0 +1,148 @@
+package game
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"go-ddd-architecture/app/domain/gametime"
+	dto "go-ddd-architecture/app/usecase/dto/game"
+	inPort "go-ddd-architecture/app/usecase/port/in/game"
+)
+
+// DevClock 為開發模式可控制的時鐘（由 infra/clock.ControlClock 實作）。
+type DevClock interface {
+	Now() time.Time
+	Advance(d time.Duration)
+	Set(t time.Time)
+	SetScale(scale float64) error
+	Scale() float64
+	Offset() time.Duration
+	Reset()
+}
+
+// DevHandler 提供僅限開發模式（server --dev）的時間控制端點：
+// 平移、設定與加速遊戲時間，讓 QA 以分鐘測試數小時的進度與 8h 離線封頂。
+type DevHandler struct {
+	uc  inPort.Usecase
+	clk DevClock
+}
+
+func NewDevHandler(uc inPort.Usecase, clk DevClock) *DevHandler {
+	return &DevHandler{uc: uc, clk: clk}
+}
+
+type devClockState struct {
+	Now    time.Time `json:"now"`
+	Offset string    `json:"offset"`
+	Scale  float64   `json:"scale"`
+}
+
+type devClockResp struct {
+	Clock     devClockState           `json:"clock"`
+	Offline   *gametime.OfflineResult `json:"offline,omitempty"`
+	ViewModel dto.ViewModelDto        `json:"viewModel"`
+}
+
+// devClockReq 為時鐘調整請求；offline=true 時視為遊戲在這段時間關閉（以離線結算，含 8h 封頂），
+// 否則視為線上持續執行（立即推進至新時間）。
+type devClockReq struct {
+	By      string  `json:"by"`
+	Now     string  `json:"now"`
+	Scale   float64 `json:"scale"`
+	Offline bool    `json:"offline"`
+}
+
+// GetClock 回傳目前的遊戲時間、相對系統時間的偏移與倍率。
+func (d *DevHandler) GetClock(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, devClockResp{Clock: d.state(), ViewModel: d.uc.GetViewModel()})
+}
+
+// PostAdvance 將遊戲時間平移 by（Go duration，如 "2h"、"-5m"）。
+func (d *DevHandler) PostAdvance(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeDevClockReq(w, r)
+	if !ok {
+		return
+	}
+	by, err := time.ParseDuration(body.By)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid by, must be a Go duration such as 2h or 30m")
+		return
+	}
+	d.shift(w, body.Offline, func() { d.clk.Advance(by) })
+}
+
+// PostSet 將遊戲時間設為 now（RFC3339）。
+func (d *DevHandler) PostSet(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeDevClockReq(w, r)
+	if !ok {
+		return
+	}
+	t, err := time.Parse(time.RFC3339, body.Now)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid now, must be RFC3339")
+		return
+	}
+	d.shift(w, body.Offline, func() { d.clk.Set(t) })
+}
+
+// PostScale 調整時間倍率（例如 10 表示 10 倍速）；不改變目前的遊戲時間。
+func (d *DevHandler) PostScale(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeDevClockReq(w, r)
+	if !ok {
+		return
+	}
+	if err := d.clk.SetScale(body.Scale); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "scale must be positive")
+		return
+	}
+	// 推進一次以喚醒排程器，使其依新倍率重新計算等待時間
+	d.shift(w, false, func() {})
+}
+
+// PostReset 回到系統時間與倍率 1。
+func (d *DevHandler) PostReset(w http.ResponseWriter, r *http.Request) {
+	d.shift(w, false, d.clk.Reset)
+}
+
+// shift 調整時鐘後推進狀態：線上模式推進至新時間，離線模式以調整前的時間為關閉時間結算。
+func (d *DevHandler) shift(w http.ResponseWriter, offline bool, change func()) {
+	var resp devClockResp
+	if offline {
+		if err := d.uc.RecordClose(d.clk.Now()); err != nil {
+			writeUsecaseError(w, err)
+			return
+		}
+		change()
+		res, err := d.uc.ClaimOffline(d.clk.Now())
+		if err != nil {
+			writeUsecaseError(w, err)
+			return
+		}
+		resp.Offline = &res
+	} else {
+		change()
+		if _, err := d.uc.AdvanceTo(d.clk.Now()); err != nil {
+			writeUsecaseError(w, err)
+			return
+		}
+	}
+	resp.Clock = d.state()
+	resp.ViewModel = d.uc.GetViewModel()
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (d *DevHandler) state() devClockState {
+	return devClockState{Now: d.clk.Now().UTC(), Offset: d.clk.Offset().String(), Scale: d.clk.Scale()}
+}
+
+func decodeDevClockReq(w http.ResponseWriter, r *http.Request) (devClockReq, bool) {
+	var body devClockReq
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+			return body, false
+		}
+	}
+	return body, true
+}
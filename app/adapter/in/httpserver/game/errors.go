@@ -0,0 +1,46 @@
+package game
+
+import (
+	"errors"
+	"net/http"
+
+	"go-ddd-architecture/app/domain/player"
+	inPort "go-ddd-architecture/app/usecase/port/in/game"
+)
+
+// domainError 描述領域錯誤對應的 HTTP 狀態與穩定錯誤碼（客戶端依 code 判斷，勿隨意更名）。
+type domainError struct {
+	err    error
+	status int
+	code   string
+}
+
+// domainErrors 為領域（及用例）錯誤 → HTTP 的集中對照表：
+// 409 表示與目前狀態衝突（稍後可能成功），422 表示請求違反遊戲規則或資源不足。
+var domainErrors = []domainError{
+	{player.ErrTaskAlreadyActive, http.StatusConflict, "task_already_active"},
+	{player.ErrQueueFull, http.StatusConflict, "queue_full"},
+	{player.ErrNoFreeSlot, http.StatusConflict, "no_free_slot"},
+	{player.ErrInsufficientKnowledge, http.StatusUnprocessableEntity, "insufficient_knowledge"},
+	{player.ErrInsufficientResearch, http.StatusUnprocessableEntity, "insufficient_research"},
+	{player.ErrLanguageLocked, http.StatusUnprocessableEntity, "language_locked"},
+	{player.ErrUnknownTaskType, http.StatusUnprocessableEntity, "unknown_task_type"},
+	{inPort.ErrNoticeNotFound, http.StatusNotFound, "notice_not_found"},
+	{inPort.ErrInvalidCommand, http.StatusBadRequest, "invalid_command"},
+}
+
+// writeUsecaseError 將用例回傳的錯誤寫為統一錯誤格式；非領域錯誤一律視為 500 internal。
+func writeUsecaseError(w http.ResponseWriter, err error) {
+	status, code := errorStatus(err)
+	writeError(w, status, code, err.Error())
+}
+
+// errorStatus 依對照表取得錯誤的 HTTP 狀態與錯誤碼。
+func errorStatus(err error) (int, string) {
+	for _, d := range domainErrors {
+		if errors.Is(err, d.err) {
+			return d.status, d.code
+		}
+	}
+	return http.StatusInternalServerError, "internal"
+}
@@ -0,0 +1,215 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// heartbeatInterval 為 SSE 無事件時的心跳間隔（註解行，避免代理或客戶端判定逾時）。
+var heartbeatInterval = 15 * time.Second
+
+// eventBufferSize 為可供續傳的最近快照數；Last-Event-ID 不在其中時改送完整快照。
+const eventBufferSize = 64
+
+// GetEvents 以 Server-Sent Events 推送 ViewModel：
+//   - 連線（或續傳版本已不在緩衝中）時先送 event: snapshot（完整 ViewModel）；
+//   - 之後每次狀態變更送 event: diff，內容為相對上一事件的 JSON Merge Patch（RFC 7386）；
+//   - id 為「啟動識別-狀態版本」，斷線後以 Last-Event-ID 續傳，只補送期間的差異；
+//     啟動識別不符（伺服器已重啟，版本重新計數）時改送完整快照；
+//   - 無事件時每 heartbeatInterval 送一次心跳註解。
+func (h *Handler) GetEvents(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	changes, cancel := h.uc.Subscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprint(w, "retry: 2000\n\n"); err != nil {
+		return
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	// 續傳：找出客戶端最後收到的版本作為差異基準
+	st := &vmStream{h: h}
+	if v, ok := h.parseEventID(r.Header.Get("Last-Event-ID")); ok {
+		if body, ok := h.events.get(v); ok {
+			st.last, st.lastVersion = body, v
+		}
+	}
+
+	send := func() error {
+		kind, version, data, err := st.next()
+		if err != nil || kind == "" {
+			return err
+		}
+		return writeEvent(w, rc, kind, h.eventID(version), data)
+	}
+
+	if err := send(); err != nil {
+		return
+	}
+	hb := time.NewTicker(heartbeatInterval)
+	defer hb.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changes:
+			if err := send(); err != nil {
+				return
+			}
+		case <-hb.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// eventID 組出 SSE 事件 id（啟動識別-狀態版本）。
+func (h *Handler) eventID(version uint64) string {
+	return h.boot + "-" + strconv.FormatUint(version, 10)
+}
+
+// parseEventID 解析本行程產生的事件 id；其他行程（重啟前）或格式不符時回傳 false。
+func (h *Handler) parseEventID(id string) (uint64, bool) {
+	boot, v, ok := strings.Cut(id, "-")
+	if !ok || boot != h.boot {
+		return 0, false
+	}
+	version, err := strconv.ParseUint(v, 10, 64)
+	return version, err == nil
+}
+
+func writeEvent(w http.ResponseWriter, rc *http.ResponseController, event, id string, data []byte) error {
+	if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", id, event, data); err != nil {
+		return err
+	}
+	return rc.Flush()
+}
+
+// vmStream 追蹤單一連線最後送出的 ViewModel，據以產生快照或差異（SSE 與 WebSocket 共用）。
+type vmStream struct {
+	h           *Handler
+	last        []byte
+	lastVersion uint64
+}
+
+// next 回傳下一個要送出的事件：尚無基準時為 snapshot，否則為相對基準的 diff；
+// 版本未變或內容相同時 kind 為空字串。
+func (s *vmStream) next() (kind string, version uint64, data []byte, err error) {
+	vm, version := s.h.uc.VersionedViewModel()
+	body, err := json.Marshal(vm)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	s.h.events.put(version, body)
+	if s.last == nil {
+		s.last, s.lastVersion = body, version
+		return "snapshot", version, body, nil
+	}
+	if version == s.lastVersion {
+		return "", version, nil, nil
+	}
+	patch, err := mergePatch(s.last, body)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	s.last, s.lastVersion = body, version
+	if patch == nil {
+		return "", version, nil, nil
+	}
+	return "diff", version, patch, nil
+}
+
+// eventBuffer 保存最近幾個版本的 ViewModel JSON，供 Last-Event-ID 續傳計算差異（所有連線共用）。
+type eventBuffer struct {
+	mu    sync.Mutex
+	order []uint64
+	items map[uint64][]byte
+}
+
+func newEventBuffer() *eventBuffer { return &eventBuffer{items: map[uint64][]byte{}} }
+
+func (b *eventBuffer) put(version uint64, body []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.items[version]; ok {
+		return
+	}
+	b.items[version] = body
+	b.order = append(b.order, version)
+	if len(b.order) > eventBufferSize {
+		delete(b.items, b.order[0])
+		b.order = b.order[1:]
+	}
+}
+
+func (b *eventBuffer) get(version uint64) ([]byte, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	body, ok := b.items[version]
+	return body, ok
+}
+
+// mergePatch 產生由 from 變成 to 的 JSON Merge Patch；兩者相同時回傳 nil。
+func mergePatch(from, to []byte) ([]byte, error) {
+	if bytes.Equal(from, to) {
+		return nil, nil
+	}
+	var a, b any
+	if err := json.Unmarshal(from, &a); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(to, &b); err != nil {
+		return nil, err
+	}
+	patch, changed := diffValue(a, b)
+	if !changed {
+		return nil, nil
+	}
+	return json.Marshal(patch)
+}
+
+// diffValue 物件逐欄遞迴比較（刪除的欄位以 null 表示），其餘型別不同即整個取代。
+func diffValue(a, b any) (any, bool) {
+	am, aok := a.(map[string]any)
+	bm, bok := b.(map[string]any)
+	if !aok || !bok {
+		if reflect.DeepEqual(a, b) {
+			return nil, false
+		}
+		return b, true
+	}
+	patch := map[string]any{}
+	for k, bv := range bm {
+		if av, ok := am[k]; ok {
+			if d, changed := diffValue(av, bv); changed {
+				patch[k] = d
+			}
+			continue
+		}
+		patch[k] = bv
+	}
+	for k := range am {
+		if _, ok := bm[k]; !ok {
+			patch[k] = nil
+		}
+	}
+	return patch, len(patch) > 0
+}
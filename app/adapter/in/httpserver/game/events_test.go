@@ -0,0 +1,116 @@
+package game
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sseEvent struct{ id, event, data string }
+
+func readEvent(t *testing.T, br *bufio.Reader) sseEvent {
+	t.Helper()
+	var ev sseEvent
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			if ev.event != "" {
+				return ev
+			}
+		case strings.HasPrefix(line, "id: "):
+			ev.id = line[4:]
+		case strings.HasPrefix(line, "event: "):
+			ev.event = line[7:]
+		case strings.HasPrefix(line, "data: "):
+			ev.data = line[6:]
+		}
+	}
+}
+
+func openStream(t *testing.T, ctx context.Context, url, lastID string) *bufio.Reader {
+	t.Helper()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url+"/api/v1/game/events", nil)
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type: %q", ct)
+	}
+	return bufio.NewReader(resp.Body)
+}
+
+func TestEvents_SnapshotDiffAndResume(t *testing.T) {
+	srv := httptest.NewServer(newTestRouter(t, RouterOptions{}))
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	br := openStream(t, ctx, srv.URL, "")
+	snap := readEvent(t, br)
+	if snap.event != "snapshot" || !strings.Contains(snap.data, `"CurrentTask":null`) {
+		t.Fatalf("first event should be a full snapshot: %+v", snap)
+	}
+
+	if resp, err := http.Post(srv.URL+"/api/v1/game/start-practice", "application/json", nil); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("start: %v %v", err, resp)
+	}
+	diff := readEvent(t, br)
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(diff.data), &patch); err != nil || diff.event != "diff" {
+		t.Fatalf("expected a diff event: %+v", diff)
+	}
+	if _, ok := patch["CurrentTask"]; !ok || patch["Knowledge"] != nil {
+		t.Fatalf("diff should only carry changed fields: %s", diff.data)
+	}
+
+	// 以最後收到的版本續傳：狀態未變時不重送快照
+	br2 := openStream(t, ctx, srv.URL, diff.id)
+	if resp, err := http.Post(srv.URL+"/api/v1/game/select-language", "application/json", strings.NewReader(`{"language":"py"}`)); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("select: %v %v", err, resp)
+	}
+	next := readEvent(t, br2)
+	if next.event != "diff" || !strings.Contains(next.data, `"CurrentLanguage":"py"`) {
+		t.Fatalf("resumed stream should continue with diffs: %+v", next)
+	}
+
+	// 未知版本（超出緩衝）改送完整快照
+	br3 := openStream(t, ctx, srv.URL, "999999")
+	if ev := readEvent(t, br3); ev.event != "snapshot" {
+		t.Fatalf("unknown Last-Event-ID should get a snapshot: %+v", ev)
+	}
+
+	// 重啟後的伺服器版本重新計數：前一個行程的 id 即使版本相同也要改送快照
+	restarted := httptest.NewServer(newTestRouter(t, RouterOptions{}))
+	defer restarted.Close()
+	rctx, rcancel := context.WithCancel(ctx)
+	defer rcancel()
+	_ = readEvent(t, openStream(t, rctx, restarted.URL, ""))
+	if ev := readEvent(t, openStream(t, rctx, restarted.URL, snap.id)); ev.event != "snapshot" {
+		t.Fatalf("id from a previous process should get a snapshot: %+v", ev)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	p, err := mergePatch([]byte(`{"a":1,"b":{"x":1,"y":2},"c":3}`), []byte(`{"a":1,"b":{"x":1,"y":5},"d":4}`))
+	if err != nil || string(p) != `{"b":{"y":5},"c":null,"d":4}` {
+		t.Fatalf("patch: %s %v", p, err)
+	}
+	if p, _ := mergePatch([]byte(`{"a":1}`), []byte(`{"a":1}`)); p != nil {
+		t.Fatalf("identical documents should give no patch: %s", p)
+	}
+}
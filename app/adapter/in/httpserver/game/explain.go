@@ -0,0 +1,13 @@
+package game
+
+import "net/http"
+
+// GetExplain 回傳各任務類型的成功率、時長與獎勵拆解（?lang= 可選）。
+func (h *Handler) GetExplain(w http.ResponseWriter, r *http.Request) {
+	out, err := h.uc.Explain(r.URL.Query().Get("lang"))
+	if err != nil {
+		writeUsecaseError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
@@ -1,8 +1,12 @@
 package game
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -10,20 +14,108 @@ import (
 	"go-ddd-architecture/app/domain/gametime"
 	dto "go-ddd-architecture/app/usecase/dto/game"
 	inPort "go-ddd-architecture/app/usecase/port/in/game"
+	outPort "go-ddd-architecture/app/usecase/port/out/game"
 )
 
 type Handler struct {
-	uc  inPort.Usecase
+	uc   inPort.Usecase
+	hist inPort.HistoryUsecase
+	// clk 為注入的時鐘；handler 不直接呼叫 time.Now，開發模式的偏移/加速才會一致生效
+	clk outPort.Clock
 	log *zap.Logger
+	// events 保存最近推送的 ViewModel，供 SSE 以 Last-Event-ID 續傳
+	events *eventBuffer
+	// boot 為每次啟動產生的隨機值：狀態版本在重啟後從頭計數，ETag 與 SSE 事件 id 需帶 boot 才不會與前一個行程的版本相符
+	boot string
 }
 
-func NewHandler(uc inPort.Usecase, log *zap.Logger) *Handler { return &Handler{uc: uc, log: log} }
+func NewHandler(uc inPort.Usecase, hist inPort.HistoryUsecase, clk outPort.Clock, log *zap.Logger) *Handler {
+	return &Handler{uc: uc, hist: hist, clk: clk, log: log, events: newEventBuffer(), boot: newBootID()}
+}
+
+// newBootID 產生 8 個十六進位字元的啟動識別。
+func newBootID() string {
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
 
+// maxViewModelWait 為長輪詢 ?wait= 的上限，避免連線無限期掛著。
+const maxViewModelWait = 60 * time.Second
+
+// GetViewModel 回傳目前的 ViewModel，ETag 為狀態版本（弱驗證：RemainingSeconds 由 EndsAt 推得，
+// 版本不變時不視為變更）：
+//   - If-None-Match 符合目前版本時回 304，不重新產生 ViewModel；
+//   - ?wait=<duration>（例如 25s，上限 maxViewModelWait）搭配 If-None-Match：版本未變時阻塞到狀態變更，逾時仍回 304。
 func (h *Handler) GetViewModel(w http.ResponseWriter, r *http.Request) {
-	vm := h.uc.GetViewModel()
+	var wait time.Duration
+	if q := r.URL.Query().Get("wait"); q != "" {
+		d, err := time.ParseDuration(q)
+		if err != nil || d < 0 {
+			writeError(w, http.StatusBadRequest, "bad_request", "invalid wait, must be a duration such as 25s")
+			return
+		}
+		wait = min(d, maxViewModelWait)
+	}
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		// 先訂閱再比對版本，避免比對後、等待前的變更被漏掉
+		var changes <-chan struct{}
+		var timeout <-chan time.Time
+		if wait > 0 {
+			ch, cancel := h.uc.Subscribe()
+			defer cancel()
+			t := time.NewTimer(wait)
+			defer t.Stop()
+			changes, timeout = ch, t.C
+		}
+		for {
+			v := h.uc.Version()
+			if !h.etagMatch(inm, v) {
+				break
+			}
+			if wait == 0 {
+				h.notModified(w, v)
+				return
+			}
+			select {
+			case <-changes:
+			case <-timeout:
+				h.notModified(w, v)
+				return
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+	vm, version := h.uc.VersionedViewModel()
+	w.Header().Set("ETag", h.etag(version))
+	w.Header().Set("Cache-Control", "no-cache")
 	writeJSON(w, http.StatusOK, vm)
 }
 
+func (h *Handler) notModified(w http.ResponseWriter, version uint64) {
+	w.Header().Set("ETag", h.etag(version))
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// etag 以啟動識別與狀態版本組成弱 ETag（W/"<boot>-<version>"）。
+func (h *Handler) etag(version uint64) string {
+	return `W/"` + h.boot + "-" + strconv.FormatUint(version, 10) + `"`
+}
+
+// etagMatch 判斷 If-None-Match（可能為逗號分隔清單或 *）是否包含目前版本；依 RFC 9110 以弱比較。
+func (h *Handler) etagMatch(ifNoneMatch string, version uint64) bool {
+	want := strings.TrimPrefix(h.etag(version), "W/")
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
 type claimReq struct {
 	AsOf string `json:"asOf"`
 }
@@ -38,7 +130,7 @@ func (h *Handler) PostClaimOffline(w http.ResponseWriter, r *http.Request) {
 	if r.Body != nil {
 		_ = json.NewDecoder(r.Body).Decode(&body)
 	}
-	now := time.Now().UTC()
+	now := h.clk.Now().UTC()
 	if body.AsOf != "" {
 		if t, err := time.Parse(time.RFC3339, body.AsOf); err == nil {
 			now = t
@@ -49,7 +141,7 @@ func (h *Handler) PostClaimOffline(w http.ResponseWriter, r *http.Request) {
 	}
 	res, err := h.uc.ClaimOffline(now)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "internal", err.Error())
+		writeUsecaseError(w, err)
 		return
 	}
 	vm := h.uc.GetViewModel()
@@ -58,9 +150,9 @@ func (h *Handler) PostClaimOffline(w http.ResponseWriter, r *http.Request) {
 
 // Start a practice task immediately
 func (h *Handler) PostStartPractice(w http.ResponseWriter, r *http.Request) {
-	now := time.Now().UTC()
+	now := h.clk.Now().UTC()
 	if err := h.uc.StartPractice(now); err != nil {
-		writeError(w, http.StatusInternalServerError, "internal", err.Error())
+		writeUsecaseError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, h.uc.GetViewModel())
@@ -68,9 +160,9 @@ func (h *Handler) PostStartPractice(w http.ResponseWriter, r *http.Request) {
 
 // Start a targeted task immediately (slightly higher reward / shorter duration)
 func (h *Handler) PostStartTargeted(w http.ResponseWriter, r *http.Request) {
-	now := time.Now().UTC()
+	now := h.clk.Now().UTC()
 	if err := h.uc.StartTargeted(now); err != nil {
-		writeError(w, http.StatusInternalServerError, "internal", err.Error())
+		writeUsecaseError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, h.uc.GetViewModel())
@@ -78,9 +170,9 @@ func (h *Handler) PostStartTargeted(w http.ResponseWriter, r *http.Request) {
 
 // Start a deploy task immediately
 func (h *Handler) PostStartDeploy(w http.ResponseWriter, r *http.Request) {
-	now := time.Now().UTC()
+	now := h.clk.Now().UTC()
 	if err := h.uc.StartDeploy(now); err != nil {
-		writeError(w, http.StatusInternalServerError, "internal", err.Error())
+		writeUsecaseError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, h.uc.GetViewModel())
@@ -88,9 +180,9 @@ func (h *Handler) PostStartDeploy(w http.ResponseWriter, r *http.Request) {
 
 // Start a research task immediately
 func (h *Handler) PostStartResearch(w http.ResponseWriter, r *http.Request) {
-	now := time.Now().UTC()
+	now := h.clk.Now().UTC()
 	if err := h.uc.StartResearch(now); err != nil {
-		writeError(w, http.StatusInternalServerError, "internal", err.Error())
+		writeUsecaseError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, h.uc.GetViewModel())
@@ -104,10 +196,10 @@ type finishResp struct {
 }
 
 func (h *Handler) PostTryFinish(w http.ResponseWriter, r *http.Request) {
-	now := time.Now().UTC()
+	now := h.clk.Now().UTC()
 	finished, reward, err := h.uc.TryFinish(now)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "internal", err.Error())
+		writeUsecaseError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, finishResp{Finished: finished, Reward: reward, ViewModel: h.uc.GetViewModel()})
@@ -128,7 +220,7 @@ func (h *Handler) PostSelectLanguage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err := h.uc.SelectLanguage(body.Language); err != nil {
-		writeError(w, http.StatusInternalServerError, "internal", err.Error())
+		writeUsecaseError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, h.uc.GetViewModel())
@@ -141,22 +233,20 @@ type buyResp struct {
 }
 
 func (h *Handler) PostBuyServer(w http.ResponseWriter, r *http.Request) {
-	ok, err := h.uc.BuyServer()
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, "internal", err.Error())
+	if err := h.uc.BuyServer(); err != nil {
+		writeUsecaseError(w, err)
 		return
 	}
-	writeJSON(w, http.StatusOK, buyResp{OK: ok, ViewModel: h.uc.GetViewModel()})
+	writeJSON(w, http.StatusOK, buyResp{OK: true, ViewModel: h.uc.GetViewModel()})
 }
 
 // Buy a GPU (consumes Knowledge; requires free slot)
 func (h *Handler) PostBuyGPU(w http.ResponseWriter, r *http.Request) {
-	ok, err := h.uc.BuyGPU()
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, "internal", err.Error())
+	if err := h.uc.BuyGPU(); err != nil {
+		writeUsecaseError(w, err)
 		return
 	}
-	writeJSON(w, http.StatusOK, buyResp{OK: ok, ViewModel: h.uc.GetViewModel()})
+	writeJSON(w, http.StatusOK, buyResp{OK: true, ViewModel: h.uc.GetViewModel()})
 }
 
 // --- shared helpers (local to game module) ---
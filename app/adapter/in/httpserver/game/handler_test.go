@@ -0,0 +1,71 @@
+package game
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func getViewModel(h http.Handler, query, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, PrefixV1+"/viewmodel"+query, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestViewModel_ETagAndConditionalGet(t *testing.T) {
+	h := newTestRouter(t, RouterOptions{})
+	first := getViewModel(h, "", "")
+	tag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || tag == "" {
+		t.Fatalf("first GET: %d etag=%q", first.Code, tag)
+	}
+	if rec := getViewModel(h, "", tag); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 || rec.Header().Get("ETag") != tag {
+		t.Fatalf("unchanged state should be 304: %d %q", rec.Code, rec.Body)
+	}
+	if rec := getViewModel(h, "", `"other", `+tag); rec.Code != http.StatusNotModified {
+		t.Fatalf("list containing the current tag should match: %d", rec.Code)
+	}
+
+	serve(h, http.MethodPost, PrefixV1+"/start-practice")
+	rec := getViewModel(h, "", tag)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == tag {
+		t.Fatalf("mutation should change the ETag: %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	if rec := getViewModel(h, "?wait=nope", tag); rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid wait: %d", rec.Code)
+	}
+
+	// 重啟後版本從頭計數：前一個行程的 ETag 不可與新行程的相同版本相符
+	restarted := newTestRouter(t, RouterOptions{})
+	if rec := getViewModel(restarted, "", first.Header().Get("ETag")); rec.Code != http.StatusOK {
+		t.Fatalf("ETag from a previous process must not match: %d", rec.Code)
+	}
+}
+
+func TestViewModel_LongPoll(t *testing.T) {
+	h := newTestRouter(t, RouterOptions{})
+	tag := getViewModel(h, "", "").Header().Get("ETag")
+
+	if rec := getViewModel(h, "?wait=20ms", tag); rec.Code != http.StatusNotModified {
+		t.Fatalf("long-poll timeout should be 304: %d", rec.Code)
+	}
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- getViewModel(h, "?wait=5s", tag) }()
+	time.Sleep(20 * time.Millisecond)
+	serve(h, http.MethodPost, PrefixV1+"/start-practice")
+	select {
+	case rec := <-done:
+		if rec.Code != http.StatusOK || rec.Header().Get("ETag") == tag {
+			t.Fatalf("long-poll should return the new state: %d %q", rec.Code, rec.Header().Get("ETag"))
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("long-poll did not wake on state change")
+	}
+}
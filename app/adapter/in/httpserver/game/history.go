@@ -0,0 +1,43 @@
+package game
+
+import (
+	"net/http"
+	"time"
+
+	"go-ddd-architecture/app/domain/stats"
+)
+
+// GetHistory 回傳統計時間序列：?res=minute|hour|day&from=RFC3339&to=RFC3339（皆可選）。
+func (h *Handler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	res := q.Get("res")
+	if res == "" {
+		res = string(stats.Minute)
+	}
+	if _, ok := stats.ParseResolution(res); !ok {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid res, must be minute|hour|day")
+		return
+	}
+	var from, to time.Time
+	for _, p := range []struct {
+		key string
+		dst *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		v := q.Get(p.key)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "bad_request", "invalid "+p.key+", must be RFC3339")
+			return
+		}
+		*p.dst = t
+	}
+	out, err := h.hist.History(res, from, to)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
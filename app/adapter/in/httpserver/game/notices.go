@@ -0,0 +1,35 @@
+package game
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type ackNoticeReq struct {
+	ID  string `json:"id"`
+	All bool   `json:"all"`
+}
+
+// PostAckNotice 確認通知（指定 id，或 all=true 全部確認），回傳最新 ViewModel。
+func (h *Handler) PostAckNotice(w http.ResponseWriter, r *http.Request) {
+	var body ackNoticeReq
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+			return
+		}
+	}
+	switch {
+	case body.All:
+		h.uc.AckAllNotices()
+	case body.ID != "":
+		if err := h.uc.AckNotice(body.ID); err != nil {
+			writeUsecaseError(w, err)
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, "bad_request", "id or all is required")
+		return
+	}
+	writeJSON(w, http.StatusOK, h.uc.GetViewModel())
+}
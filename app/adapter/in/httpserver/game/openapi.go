@@ -0,0 +1,284 @@
+package game
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PathOpenAPI 為 OpenAPI 文件的路徑。
+const PathOpenAPI = "/api/v1/openapi.json"
+
+// Doc 為路由的文件描述，OpenAPI 文件由路由表與這些型別反射產生（不另外手寫）。
+// Request/Response 為請求本體與 200 回應的範例值（僅取其型別）；nil 表示無本體。
+type Doc struct {
+	Summary  string
+	Query    []Param
+	Request  any
+	Response any
+	// ContentType 覆寫回應的媒體類型（例如 SSE）；空字串為 application/json
+	ContentType string
+	// Status 覆寫成功狀態碼（例如 WebSocket 升級為 101）；0 為 200
+	Status int
+	// Conditional 表示支援 ETag / If-None-Match（可回 304）
+	Conditional bool
+}
+
+// Param 為查詢參數。
+type Param struct {
+	Name        string
+	Description string
+}
+
+func doc(summary string, req, resp any) Doc {
+	return Doc{Summary: summary, Request: req, Response: resp}
+}
+
+// OpenAPI 依路由表產生 OpenAPI 3 文件；dev 為 nil 時不含開發模式端點。
+func OpenAPI(h *Handler, dev *DevHandler) map[string]any {
+	g := &schemaGen{defs: map[string]any{}, owner: map[string]reflect.Type{}}
+	g.defs["ErrorEnvelope"] = g.schema(reflect.TypeOf(errorEnvelope{}))
+	paths := map[string]any{}
+	add := func(prefix, tag string, routes []Route) {
+		for _, rt := range routes {
+			p, _ := paths[prefix+rt.Path].(map[string]any)
+			if p == nil {
+				p = map[string]any{}
+				paths[prefix+rt.Path] = p
+			}
+			p[strings.ToLower(rt.Method)] = g.operation(tag, rt)
+		}
+	}
+	add(PrefixV1, "game", h.Routes())
+	if dev != nil {
+		add(PrefixDev, "dev", dev.Routes())
+	}
+	paths[PathOpenAPI] = map[string]any{"get": map[string]any{
+		"tags":      []string{"meta"},
+		"summary":   "本文件（OpenAPI 3）；不需驗證",
+		"security":  []any{},
+		"responses": map[string]any{"200": map[string]any{"description": "OK", "content": jsonContent(map[string]any{"type": "object"})}},
+	}}
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "Idle Coder game API",
+			"version": "1",
+			"description": "由路由表與 DTO 型別產生。舊前綴 " + PrefixLegacy + " 提供相同端點但已棄用；" +
+				"錯誤一律為 ErrorEnvelope，code 為穩定錯誤碼。" +
+				"啟用驗證時需帶 Authorization: Bearer <token>（token 檔見 docs/http-adapter.md）；唯讀 token 只能使用 GET。",
+		},
+		"paths":    paths,
+		"security": []any{map[string]any{"bearerAuth": []any{}}},
+		"components": map[string]any{
+			"schemas":         g.defs,
+			"securitySchemes": map[string]any{"bearerAuth": map[string]any{"type": "http", "scheme": "bearer"}},
+		},
+	}
+}
+
+// serveOpenAPI 回傳預先序列化的 OpenAPI 文件。
+func serveOpenAPI(spec map[string]any) http.HandlerFunc {
+	body, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		panic("openapi: " + err.Error())
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write(body)
+	}
+}
+
+func (g *schemaGen) operation(tag string, rt Route) map[string]any {
+	d := rt.Doc
+	op := map[string]any{
+		"tags":        []string{tag},
+		"summary":     d.Summary,
+		"operationId": operationID(rt),
+	}
+	var params []any
+	for _, p := range d.Query {
+		params = append(params, map[string]any{
+			"name": p.Name, "in": "query", "required": false,
+			"description": p.Description, "schema": map[string]any{"type": "string"},
+		})
+	}
+	if d.Conditional {
+		params = append(params, map[string]any{
+			"name": "If-None-Match", "in": "header", "required": false,
+			"description": "先前回應的 ETag；狀態版本未變時回 304", "schema": map[string]any{"type": "string"},
+		})
+	}
+	if rt.Method == http.MethodPost {
+		params = append(params, map[string]any{
+			"name": "Idempotency-Key", "in": "header", "required": false,
+			"description": "重試時沿用相同金鑰，伺服器重播原回應而不重複執行", "schema": map[string]any{"type": "string", "maxLength": 255},
+		})
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+	if d.Request != nil {
+		op["requestBody"] = map[string]any{"content": jsonContent(g.schema(reflect.TypeOf(d.Request)))}
+	}
+	status := d.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	ok := map[string]any{"description": http.StatusText(status)}
+	switch {
+	case d.ContentType != "":
+		ok["content"] = map[string]any{d.ContentType: map[string]any{"schema": map[string]any{"type": "string"}}}
+	case d.Response != nil:
+		ok["content"] = jsonContent(g.schema(reflect.TypeOf(d.Response)))
+	}
+	errResp := map[string]any{
+		"description": "錯誤（code 見 docs/http-adapter.md）",
+		"content":     jsonContent(map[string]any{"$ref": "#/components/schemas/ErrorEnvelope"}),
+	}
+	responses := map[string]any{strconv.Itoa(status): ok, "default": errResp}
+	if d.Conditional {
+		etag := map[string]any{"ETag": map[string]any{"description": "啟動識別與狀態版本（弱 ETag，W/\"<boot>-<version>\"）", "schema": map[string]any{"type": "string"}}}
+		ok["headers"] = etag
+		responses["304"] = map[string]any{"description": http.StatusText(http.StatusNotModified), "headers": etag}
+	}
+	op["responses"] = responses
+	return op
+}
+
+// operationID 由方法與路徑組成，例如 POST /notices/ack → postNoticesAck。
+func operationID(rt Route) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(rt.Method))
+	for _, part := range strings.FieldsFunc(rt.Path, func(r rune) bool { return r == '/' || r == '-' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func jsonContent(schema any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+// schemaGen 以反射將 Go 型別轉為 JSON Schema（遵循 encoding/json 的欄位規則）：
+// 匯出的具名 struct 收錄為 components（以型別名稱命名），其餘內嵌。
+type schemaGen struct {
+	defs  map[string]any
+	owner map[string]reflect.Type
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+func (g *schemaGen) schema(t reflect.Type) map[string]any {
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case rawType:
+		return map[string]any{"description": "任意 JSON"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		inner := g.schema(t.Elem())
+		if _, isRef := inner["$ref"]; isRef {
+			return map[string]any{"allOf": []any{inner}, "nullable": true}
+		}
+		inner["nullable"] = true
+		return inner
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number", "format": "double"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		// nil slice 編碼為 null
+		return map[string]any{"type": "array", "items": g.schema(t.Elem()), "nullable": true}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem()), "nullable": true}
+	case reflect.Interface:
+		return map[string]any{"description": "任意 JSON"}
+	case reflect.Struct:
+		if t.Name() == "" || !isExported(t.Name()) {
+			return g.object(t)
+		}
+		name := g.defName(t)
+		if _, ok := g.defs[name]; !ok {
+			g.defs[name] = nil // 先佔位，避免遞迴型別無限展開
+			g.defs[name] = g.object(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]any{}
+}
+
+// defName 以型別名稱命名；不同套件同名時加上套件名前綴。
+func (g *schemaGen) defName(t reflect.Type) string {
+	name := t.Name()
+	if prev, ok := g.owner[name]; ok && prev != t {
+		pkg := t.PkgPath()
+		name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + name
+	}
+	g.owner[name] = t
+	return name
+}
+
+func (g *schemaGen) object(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	var required []string
+	for _, f := range jsonFields(t) {
+		props[f.name] = g.schema(f.typ)
+		if !f.omitempty {
+			required = append(required, f.name)
+		}
+	}
+	out := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		sort.Strings(required)
+		out["required"] = required
+	}
+	return out
+}
+
+type jsonField struct {
+	name      string
+	typ       reflect.Type
+	omitempty bool
+}
+
+// jsonFields 列出 struct 經 encoding/json 編碼後的欄位（含展開的匿名嵌入欄位）。
+func jsonFields(t reflect.Type) []jsonField {
+	var out []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			out = append(out, jsonFields(f.Type)...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		out = append(out, jsonField{name: name, typ: f.Type, omitempty: strings.Contains(","+opts+",", ",omitempty,")})
+	}
+	return out
+}
+
+func isExported(name string) bool { return name != "" && strings.ToUpper(name[:1]) == name[:1] }
@@ -0,0 +1,181 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func loadSpec(t *testing.T, h http.Handler) map[string]any {
+	t.Helper()
+	rec := serve(h, http.MethodGet, PathOpenAPI)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s: %d", PathOpenAPI, rec.Code)
+	}
+	var spec map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("decode spec: %v", err)
+	}
+	return spec
+}
+
+func TestOpenAPI_EveryRouteDocumented(t *testing.T) {
+	h := newTestRouter(t, RouterOptions{})
+	spec := loadSpec(t, h)
+	paths := spec["paths"].(map[string]any)
+	routes := (&Handler{}).Routes()
+	for _, rt := range routes {
+		if rt.Doc.Summary == "" {
+			t.Errorf("%s %s has no Doc", rt.Method, rt.Path)
+		}
+		ops, _ := paths[PrefixV1+rt.Path].(map[string]any)
+		if _, ok := ops[strings.ToLower(rt.Method)]; !ok {
+			t.Errorf("%s %s missing from spec", rt.Method, PrefixV1+rt.Path)
+		}
+	}
+	for _, rt := range (&DevHandler{}).Routes() {
+		if rt.Doc.Summary == "" {
+			t.Errorf("dev %s %s has no Doc", rt.Method, rt.Path)
+		}
+		if _, ok := paths[PrefixDev+rt.Path]; ok {
+			t.Errorf("dev route %s documented although dev mode is off", rt.Path)
+		}
+	}
+	schemas := spec["components"].(map[string]any)["schemas"].(map[string]any)
+	vm := schemas["ViewModelDto"].(map[string]any)["properties"].(map[string]any)
+	if _, ok := vm["CurrentTask"]; !ok {
+		t.Fatalf("ViewModelDto should use the PascalCase keys it serializes with: %v", vm)
+	}
+}
+
+// TestOpenAPI_ResponsesMatchSchema 以實際請求驗證處理器的回應符合文件宣告的 schema（含不得多出未宣告的欄位）。
+func TestOpenAPI_ResponsesMatchSchema(t *testing.T) {
+	h := newTestRouter(t, RouterOptions{})
+	spec := loadSpec(t, h)
+	cases := []struct{ method, path, body string }{
+		{http.MethodGet, "/viewmodel", ""},
+		{http.MethodPost, "/claim-offline", `{}`},
+		{http.MethodPost, "/start-practice", ""},
+		{http.MethodPost, "/try-finish", ""},
+		{http.MethodPost, "/enqueue", `{"type":"Practice"}`},
+		{http.MethodPost, "/select-language", `{"language":"go"}`},
+		{http.MethodPost, "/automation", `{"enabled":true,"autoPractice":true}`},
+		{http.MethodGet, "/history", ""},
+		{http.MethodGet, "/explain", ""},
+		{http.MethodGet, "/advice", ""},
+		{http.MethodPost, "/commands", `{"command":"try-finish"}`},
+		{http.MethodPost, "/commands/batch", `{"commands":[{"command":"enqueue","args":{"type":"Research"}}]}`},
+		{http.MethodGet, "/commands", ""},
+		{http.MethodPost, "/buy-server", ""}, // 資源不足 → ErrorEnvelope
+	}
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(c.method, PrefixV1+c.path, strings.NewReader(c.body)))
+		status := "default"
+		if rec.Code < 400 {
+			status = fmt.Sprint(rec.Code)
+		}
+		op := spec["paths"].(map[string]any)[PrefixV1+c.path].(map[string]any)[strings.ToLower(c.method)].(map[string]any)
+		resp, ok := op["responses"].(map[string]any)[status].(map[string]any)
+		if !ok {
+			t.Errorf("%s %s: status %d not documented", c.method, c.path, rec.Code)
+			continue
+		}
+		schema := resp["content"].(map[string]any)["application/json"].(map[string]any)["schema"]
+		var v any
+		if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+			t.Fatalf("%s %s: %v", c.method, c.path, err)
+		}
+		for _, problem := range validate(spec, schema, v, c.path) {
+			t.Errorf("%s %s: %s", c.method, c.path, problem)
+		}
+	}
+}
+
+// validate 為測試用的最小 JSON Schema 驗證：型別、必要欄位、未宣告欄位與 $ref/allOf/nullable。
+func validate(spec map[string]any, schema any, v any, at string) []string {
+	s, _ := schema.(map[string]any)
+	if ref, ok := s["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		return validate(spec, spec["components"].(map[string]any)["schemas"].(map[string]any)[name], v, at)
+	}
+	if v == nil {
+		if s["nullable"] == true {
+			return nil
+		}
+		return []string{at + ": null not allowed"}
+	}
+	if all, ok := s["allOf"].([]any); ok {
+		var out []string
+		for _, sub := range all {
+			out = append(out, validate(spec, sub, v, at)...)
+		}
+		return out
+	}
+	typ, _ := s["type"].(string)
+	switch typ {
+	case "":
+		return nil
+	case "object":
+		m, ok := v.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: want object, got %T", at, v)}
+		}
+		var out []string
+		props, _ := s["properties"].(map[string]any)
+		if extra, ok := s["additionalProperties"]; ok {
+			for k, val := range m {
+				out = append(out, validate(spec, extra, val, at+"."+k)...)
+			}
+			return out
+		}
+		req, _ := s["required"].([]any)
+		for _, r := range req {
+			if _, ok := m[r.(string)]; !ok {
+				out = append(out, at+": missing required "+r.(string))
+			}
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			ps, ok := props[k]
+			if !ok {
+				out = append(out, at+": undocumented field "+k)
+				continue
+			}
+			out = append(out, validate(spec, ps, m[k], at+"."+k)...)
+		}
+		return out
+	case "array":
+		a, ok := v.([]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: want array, got %T", at, v)}
+		}
+		var out []string
+		for i, item := range a {
+			out = append(out, validate(spec, s["items"], item, fmt.Sprintf("%s[%d]", at, i))...)
+		}
+		return out
+	case "integer", "number":
+		f, ok := v.(float64)
+		if !ok || (typ == "integer" && f != float64(int64(f))) {
+			return []string{fmt.Sprintf("%s: want %s, got %v", at, typ, v)}
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			return []string{fmt.Sprintf("%s: want string, got %T", at, v)}
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return []string{fmt.Sprintf("%s: want boolean, got %T", at, v)}
+		}
+	}
+	return nil
+}
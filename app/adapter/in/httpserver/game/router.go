@@ -2,39 +2,186 @@ package game
 
 import (
 	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	dto "go-ddd-architecture/app/usecase/dto/game"
+	outPort "go-ddd-architecture/app/usecase/port/out/game"
+)
+
+// 路由前綴：路由表只宣告一次，再掛載到各前綴下。
+const (
+	PrefixV1     = "/api/v1/game"
+	PrefixLegacy = "/api/game" // 已棄用，回應帶 Deprecation/Sunset 標頭
+	PrefixDev    = "/api/v1/dev"
 )
 
+// Route 描述一個端點：HTTP 方法、相對於前綴的路徑、處理器與文件（供 OpenAPI 產生）。
+type Route struct {
+	Method  string
+	Path    string
+	Handler http.HandlerFunc
+	Doc     Doc
+}
+
+// Routes 為 game 模組的路由表（唯一宣告處）；狀態變更一律為 POST。
+func (h *Handler) Routes() []Route {
+	vm := dto.ViewModelDto{}
+	return []Route{
+		{http.MethodGet, "/viewmodel", h.GetViewModel, Doc{Summary: "目前的 ViewModel（支援 ETag 條件式 GET 與長輪詢）", Response: vm, Conditional: true, Query: []Param{
+			{"wait", "長輪詢：搭配 If-None-Match，版本未變時最多等待此時間（例如 25s，上限 60s），逾時回 304"},
+		}}},
+		{http.MethodGet, "/events", h.GetEvents, Doc{Summary: "ViewModel 推送（SSE：snapshot + JSON Merge Patch diff）", ContentType: "text/event-stream"}},
+		{http.MethodGet, "/ws", h.GetSocket, Doc{Summary: "WebSocket 雙向通道（命令 + 狀態推送）", Status: http.StatusSwitchingProtocols}},
+		{http.MethodPost, "/claim-offline", h.PostClaimOffline, doc("結算離線收益", claimReq{}, claimResp{})},
+		{http.MethodPost, "/start-practice", h.PostStartPractice, doc("開始 Practice 任務", nil, vm)},
+		{http.MethodPost, "/start-targeted", h.PostStartTargeted, doc("開始 Targeted 任務", nil, vm)},
+		{http.MethodPost, "/start-deploy", h.PostStartDeploy, doc("開始 Deploy 任務", nil, vm)},
+		{http.MethodPost, "/start-research", h.PostStartResearch, doc("開始 Research 任務", nil, vm)},
+		{http.MethodPost, "/try-finish", h.PostTryFinish, doc("推進至現在並回報結算", nil, finishResp{})},
+		{http.MethodPost, "/upgrade-knowledge", h.PostUpgradeKnowledge, doc("升級目前語言", nil, vm)},
+		{http.MethodPost, "/select-language", h.PostSelectLanguage, doc("切換目前語言", selectLangReq{}, vm)},
+		{http.MethodPost, "/buy-server", h.PostBuyServer, doc("購買伺服器", nil, buyResp{})},
+		{http.MethodPost, "/buy-gpu", h.PostBuyGPU, doc("購買 GPU", nil, buyResp{})},
+		{http.MethodGet, "/history", h.GetHistory, Doc{Summary: "統計時間序列", Response: dto.HistoryDto{}, Query: []Param{
+			{"res", "minute | hour | day（預設 minute）"}, {"from", "RFC3339"}, {"to", "RFC3339"},
+		}}},
+		{http.MethodGet, "/explain", h.GetExplain, Doc{Summary: "任務成功率、時長與獎勵拆解", Response: dto.ExplainDto{}, Query: []Param{
+			{"lang", "以指定語言計算（不切換目前語言）"},
+		}}},
+		{http.MethodGet, "/advice", h.GetAdvice, doc("進度預測與 ROI 建議", nil, dto.AdviceDto{})},
+		{http.MethodPost, "/automation", h.PostAutomation, doc("設定伺服器端自動化", automationReq{}, vm)},
+		{http.MethodPost, "/enqueue", h.PostEnqueueTask, doc("將任務加入佇列", enqueueReq{}, vm)},
+		{http.MethodPost, "/notices/ack", h.PostAckNotice, doc("確認通知", ackNoticeReq{}, vm)},
+		{http.MethodGet, "/commands", h.GetAudit, doc("命令稽核紀錄（最新在前）", nil, []dto.AuditEntryDto{})},
+		{http.MethodPost, "/commands", h.PostCommand, doc("執行單一命令", commandReq{}, commandResp{})},
+		{http.MethodPost, "/commands/batch", h.PostCommandBatch, doc("原子地執行多個命令", batchReq{}, batchResp{})},
+	}
+}
+
+// Routes 為開發模式時鐘控制端點（掛載於 PrefixDev）。
+func (d *DevHandler) Routes() []Route {
+	return []Route{
+		{http.MethodGet, "/clock", d.GetClock, doc("目前的遊戲時間、偏移與倍率", nil, devClockResp{})},
+		{http.MethodPost, "/clock/advance", d.PostAdvance, doc("平移遊戲時間", devClockReq{}, devClockResp{})},
+		{http.MethodPost, "/clock/set", d.PostSet, doc("設定遊戲時間", devClockReq{}, devClockResp{})},
+		{http.MethodPost, "/clock/scale", d.PostScale, doc("調整時間倍率", devClockReq{}, devClockResp{})},
+		{http.MethodPost, "/clock/reset", d.PostReset, doc("回到系統時間", nil, devClockResp{})},
+	}
+}
+
+// RouterOptions 為路由設定。
+type RouterOptions struct {
+	// LegacySunset 為舊前綴的停用時間：非零時以 Sunset 標頭公告，超過後舊前綴回 410 gone。
+	LegacySunset time.Time
+	// WallClock 為判斷停用時間的真實時鐘；不可用遊戲時鐘（開發模式可平移或加速）。nil 時使用系統時間。
+	WallClock outPort.Clock
+}
+
 type Router struct {
 	mux *http.ServeMux
 }
 
-func NewRouter(h *Handler) *Router {
+// NewRouter 建立 game 模組路由；dev 非 nil（server --dev）時另外掛載時間控制端點。
+func NewRouter(h *Handler, dev *DevHandler, opts RouterOptions) *Router {
 	mux := http.NewServeMux()
-	mux.HandleFunc("/api/v1/game/viewmodel", h.GetViewModel)
-	mux.HandleFunc("/api/v1/game/claim-offline", h.PostClaimOffline)
-	mux.HandleFunc("/api/v1/game/start-practice", h.PostStartPractice)
-	mux.HandleFunc("/api/v1/game/start-targeted", h.PostStartTargeted)
-	mux.HandleFunc("/api/v1/game/start-deploy", h.PostStartDeploy)
-	mux.HandleFunc("/api/v1/game/start-research", h.PostStartResearch)
-	mux.HandleFunc("/api/v1/game/try-finish", h.PostTryFinish)
-	mux.HandleFunc("/api/v1/game/upgrade-knowledge", h.PostUpgradeKnowledge)
-	mux.HandleFunc("/api/v1/game/select-language", h.PostSelectLanguage)
-	mux.HandleFunc("/api/v1/game/buy-server", h.PostBuyServer)
-	mux.HandleFunc("/api/v1/game/buy-gpu", h.PostBuyGPU)
-
-	// legacy
-	mux.HandleFunc("/api/game/viewmodel", h.GetViewModel)
-	mux.HandleFunc("/api/game/claim-offline", h.PostClaimOffline)
-	mux.HandleFunc("/api/game/start-practice", h.PostStartPractice)
-	mux.HandleFunc("/api/game/start-targeted", h.PostStartTargeted)
-	mux.HandleFunc("/api/game/start-deploy", h.PostStartDeploy)
-	mux.HandleFunc("/api/game/start-research", h.PostStartResearch)
-	mux.HandleFunc("/api/game/try-finish", h.PostTryFinish)
-	mux.HandleFunc("/api/game/upgrade-knowledge", h.PostUpgradeKnowledge)
-	mux.HandleFunc("/api/game/select-language", h.PostSelectLanguage)
-	mux.HandleFunc("/api/game/buy-server", h.PostBuyServer)
-	mux.HandleFunc("/api/game/buy-gpu", h.PostBuyGPU)
+	now := time.Now
+	if opts.WallClock != nil {
+		now = opts.WallClock.Now
+	}
+	mount(mux, PrefixV1, h.Routes(), nil)
+	mount(mux, PrefixLegacy, h.Routes(), func(path string, next http.Handler) http.Handler {
+		return legacy(PrefixV1+path, opts.LegacySunset, now, next)
+	})
+	if dev != nil {
+		mount(mux, PrefixDev, dev.Routes(), nil)
+	}
+	mount(mux, "", []Route{{Method: http.MethodGet, Path: PathOpenAPI, Handler: serveOpenAPI(OpenAPI(h, dev))}}, nil)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "not_found", "no such endpoint: "+r.URL.Path)
+	})
 	return &Router{mux: mux}
 }
 
 func (r *Router) Handler() http.Handler { return r.mux }
+
+// Route 回傳請求對應的路由樣式（例如 /api/v1/game/viewmodel）；不存在的路徑回傳空字串。
+// 供 metrics 以有限的路由集合作為標籤，而非任意的請求路徑。
+func (r *Router) Route(req *http.Request) string {
+	if _, pattern := r.mux.Handler(req); pattern != "/" {
+		return pattern
+	}
+	return ""
+}
+
+// mount 將路由表掛載到前綴下：同一路徑的各方法合併為一個處理器，
+// 不支援的方法回 405 並附 Allow 標頭；wrap 可為每個路徑包上額外行為（例如舊前綴的棄用標頭）。
+func mount(mux *http.ServeMux, prefix string, routes []Route, wrap func(path string, next http.Handler) http.Handler) {
+	byPath := map[string]map[string]http.HandlerFunc{}
+	var order []string
+	for _, rt := range routes {
+		if byPath[rt.Path] == nil {
+			byPath[rt.Path] = map[string]http.HandlerFunc{}
+			order = append(order, rt.Path)
+		}
+		byPath[rt.Path][rt.Method] = rt.Handler
+	}
+	for _, path := range order {
+		var h http.Handler = newMethodHandler(byPath[path])
+		if wrap != nil {
+			h = wrap(path, h)
+		}
+		mux.Handle(prefix+path, h)
+	}
+}
+
+// methodHandler 依 HTTP 方法分派；GET 端點同時接受 HEAD，OPTIONS 回 204 與 Allow。
+type methodHandler struct {
+	methods map[string]http.HandlerFunc
+	allow   string
+}
+
+func newMethodHandler(methods map[string]http.HandlerFunc) methodHandler {
+	if get, ok := methods[http.MethodGet]; ok {
+		if _, ok := methods[http.MethodHead]; !ok {
+			methods[http.MethodHead] = get
+		}
+	}
+	allow := []string{http.MethodOptions}
+	for m := range methods {
+		allow = append(allow, m)
+	}
+	sort.Strings(allow)
+	return methodHandler{methods: methods, allow: strings.Join(allow, ", ")}
+}
+
+func (m methodHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h, ok := m.methods[r.Method]; ok {
+		h(w, r)
+		return
+	}
+	w.Header().Set("Allow", m.allow)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method+" not allowed; allowed: "+m.allow)
+}
+
+// legacy 為舊前綴加上棄用標頭（Deprecation、Link 指向 v1 路徑、選用的 Sunset）；
+// 超過 sunset 後回 410 gone，提示改用 v1。
+func legacy(successor string, sunset time.Time, now func() time.Time, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+successor+`>; rel="successor-version"`)
+		if !sunset.IsZero() {
+			w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			if !now().Before(sunset) {
+				writeError(w, http.StatusGone, "gone", "legacy endpoint removed; use "+successor)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
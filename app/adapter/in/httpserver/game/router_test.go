@@ -0,0 +1,77 @@
+package game
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go-ddd-architecture/app/domain/gametime"
+	"go-ddd-architecture/app/infra/memory"
+	usecase "go-ddd-architecture/app/usecase/game"
+)
+
+type fixedClock struct{ t time.Time }
+
+func (c fixedClock) Now() time.Time { return c.t }
+
+func newTestRouter(t *testing.T, opts RouterOptions) http.Handler {
+	t.Helper()
+	clk := fixedClock{t: time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)}
+	uc := usecase.NewInteractor(memory.NewInMemoryRepo(), clk, gametime.NewOfflineCalculator())
+	if err := uc.Initialize(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	hist := usecase.NewHistoryService(uc, memory.NewInMemoryHistory(), clk)
+	return NewRouter(NewHandler(uc, hist, clk, zap.NewNop()), nil, opts).Handler()
+}
+
+func serve(h http.Handler, method, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestRouter_MethodAware(t *testing.T) {
+	h := newTestRouter(t, RouterOptions{})
+
+	if rec := serve(h, http.MethodGet, "/api/v1/game/start-practice"); rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "OPTIONS, POST" {
+		t.Fatalf("GET on a mutating route: %d Allow=%q", rec.Code, rec.Header().Get("Allow"))
+	}
+	if rec := serve(h, http.MethodPost, "/api/v1/game/start-practice"); rec.Code != http.StatusOK {
+		t.Fatalf("POST start-practice: %d %s", rec.Code, rec.Body)
+	}
+	if rec := serve(h, http.MethodOptions, "/api/v1/game/commands"); rec.Code != http.StatusNoContent || rec.Header().Get("Allow") != "GET, HEAD, OPTIONS, POST" {
+		t.Fatalf("OPTIONS commands: %d Allow=%q", rec.Code, rec.Header().Get("Allow"))
+	}
+	if rec := serve(h, http.MethodGet, "/api/v1/game/nope"); rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown path: %d", rec.Code)
+	}
+	if rec := serve(h, http.MethodGet, "/api/v1/game/viewmodel"); rec.Header().Get("Deprecation") != "" {
+		t.Fatalf("v1 routes must not be marked deprecated")
+	}
+}
+
+func TestRouter_LegacyPrefixDeprecatedAndSunset(t *testing.T) {
+	wall := fixedClock{t: time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)}
+	h := newTestRouter(t, RouterOptions{LegacySunset: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), WallClock: wall})
+	rec := serve(h, http.MethodGet, "/api/game/viewmodel")
+	if rec.Code != http.StatusOK || rec.Header().Get("Deprecation") != "true" ||
+		rec.Header().Get("Sunset") != "Thu, 01 Jan 2026 00:00:00 GMT" ||
+		rec.Header().Get("Link") != `</api/v1/game/viewmodel>; rel="successor-version"` {
+		t.Fatalf("legacy headers: %d %v", rec.Code, rec.Header())
+	}
+
+	past := newTestRouter(t, RouterOptions{LegacySunset: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), WallClock: wall})
+	if rec := serve(past, http.MethodGet, "/api/game/viewmodel"); rec.Code != http.StatusGone {
+		t.Fatalf("legacy after sunset should be 410, got %d", rec.Code)
+	}
+
+	// 遊戲時鐘（2025-08-10）已超過停用時間，但真實時間未到：舊前綴仍可用
+	early := newTestRouter(t, RouterOptions{LegacySunset: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), WallClock: fixedClock{t: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)}})
+	if rec := serve(early, http.MethodGet, "/api/game/viewmodel"); rec.Code != http.StatusOK {
+		t.Fatalf("sunset must follow the wall clock, not the game clock: %d", rec.Code)
+	}
+}
@@ -0,0 +1,218 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	inPort "go-ddd-architecture/app/usecase/port/in/game"
+	"go-ddd-architecture/internal/apitoken"
+	"go-ddd-architecture/internal/websocket"
+)
+
+// socketReq 為客戶端經 WebSocket 送出的訊息；id 由客戶端產生，回應原樣帶回以便對應。
+//   - type=command：單一命令（command/args 同 POST /commands）
+//   - type=batch：批次命令（commands 同 POST /commands/batch）
+//   - type=viewmodel：要求重送完整快照
+type socketReq struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	Command  string          `json:"command,omitempty"`
+	Args     json.RawMessage `json:"args,omitempty"`
+	Commands []commandReq    `json:"commands,omitempty"`
+}
+
+// socketMsg 為伺服器送出的訊息：
+//   - type=snapshot/diff：狀態推送（data 為完整 ViewModel 或 JSON Merge Patch），version 為狀態版本
+//   - type=result：請求成功（id 對應請求，result 為命令結果）
+//   - type=error：請求失敗（error 同 HTTP 錯誤格式，status 為對應的 HTTP 狀態碼；批次失敗時 result 仍帶各步驟結果）
+type socketMsg struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Version uint64          `json:"version,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Status  int             `json:"status,omitempty"`
+	Error   *httpError      `json:"error,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// MessageLimiter 依命令對應的 HTTP 方法與路徑扣除速率預算；不允許時回傳需等待的時間。
+// 由速率限制 middleware 放入升級請求的 context：握手只計一次請求，連線內的每則命令仍須套用與 HTTP 相同的預算。
+type MessageLimiter func(method, path string) (ok bool, retryAfter time.Duration)
+
+type messageLimiterKey struct{}
+
+// WithMessageLimiter 回傳帶有 MessageLimiter 的 context。
+func WithMessageLimiter(ctx context.Context, l MessageLimiter) context.Context {
+	return context.WithValue(ctx, messageLimiterKey{}, l)
+}
+
+// GetSocket 將連線升級為 WebSocket，於同一連線雙向傳遞命令與狀態：
+// 連線後先送 snapshot，之後每次狀態變更送 diff；命令的結果一律在反映該命令的 diff 之後送出，
+// 因此客戶端收到 result 時本地狀態已是最新。所有寫入由單一迴圈進行，訊息順序與伺服器處理順序一致。
+func (h *Handler) GetSocket(w http.ResponseWriter, r *http.Request) {
+	if !websocket.IsUpgrade(r) {
+		writeError(w, http.StatusBadRequest, "bad_request", "websocket upgrade required")
+		return
+	}
+	changes, cancel := h.uc.Subscribe()
+	defer cancel()
+	conn, err := websocket.Upgrade(w, r)
+	if err != nil {
+		if errors.Is(err, websocket.ErrBadHandshake) {
+			writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		}
+		return
+	}
+	defer conn.Close()
+	// 驗證 middleware 只依 HTTP 方法限制範圍；唯讀 token 在此仍可訂閱推送，但不可送出命令
+	scope, authed := apitoken.ScopeFrom(r.Context())
+	readOnly := authed && !scope.CanWrite()
+	limit, _ := r.Context().Value(messageLimiterKey{}).(MessageLimiter)
+
+	// 讀取由獨立 goroutine 進行，經 channel 交給寫入迴圈，確保寫入不併發
+	reqs := make(chan socketReq)
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				readErr <- err
+				return
+			}
+			var req socketReq
+			if err := json.Unmarshal(data, &req); err != nil {
+				req = socketReq{Type: "invalid"}
+			}
+			select {
+			case reqs <- req:
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}()
+
+	st := &vmStream{h: h}
+	write := func(m socketMsg) error {
+		b, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		return conn.WriteMessage(websocket.OpText, b)
+	}
+	push := func() error {
+		kind, version, data, err := st.next()
+		if err != nil || kind == "" {
+			return err
+		}
+		return write(socketMsg{Type: kind, Version: version, Data: data})
+	}
+
+	if err := push(); err != nil {
+		return
+	}
+	ping := time.NewTicker(heartbeatInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			conn.CloseWith(websocket.CloseGoingAway, "server shutting down")
+			return
+		case err := <-readErr:
+			var ce *websocket.CloseError
+			if !errors.As(err, &ce) {
+				h.log.Debug("websocket read failed", zap.Error(err))
+			}
+			return
+		case req := <-reqs:
+			if req.Type == "viewmodel" {
+				st.last = nil
+			}
+			reply := h.handleSocket(req, readOnly, limit)
+			// 先推送命令造成的狀態變更，再回覆結果
+			if err := push(); err != nil {
+				return
+			}
+			reply.Version = st.lastVersion
+			if err := write(reply); err != nil {
+				return
+			}
+		case <-changes:
+			if err := push(); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteMessage(websocket.OpPing, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handleSocket 執行單一請求並組成回覆（不含版本）；錯誤碼與 HTTP 端點一致。
+func (h *Handler) handleSocket(req socketReq, readOnly bool, limit MessageLimiter) socketMsg {
+	reply := socketMsg{ID: req.ID, Type: "result"}
+	fail := func(status int, code, msg string) socketMsg {
+		reply.Type, reply.Status, reply.Error = "error", status, &httpError{Code: code, Message: msg}
+		return reply
+	}
+	if readOnly && (req.Type == "command" || req.Type == "batch") {
+		return fail(http.StatusForbidden, "forbidden", "read-only token cannot send commands")
+	}
+	if limit != nil && (req.Type == "command" || req.Type == "batch") {
+		path := PrefixV1 + "/commands"
+		if req.Type == "batch" {
+			path += "/batch"
+		}
+		if ok, wait := limit(http.MethodPost, path); !ok {
+			secs := strconv.Itoa(int(math.Ceil(wait.Seconds())))
+			return fail(http.StatusTooManyRequests, "rate_limited", "too many requests; retry after "+secs+"s")
+		}
+	}
+	switch req.Type {
+	case "command":
+		cmd, err := inPort.DecodeCommand(req.Command, req.Args)
+		if err != nil {
+			return fail(failure(err))
+		}
+		res, err := h.uc.Dispatch(cmd)
+		if err != nil {
+			return fail(failure(err))
+		}
+		reply.Result = res
+	case "batch":
+		cmds := make([]inPort.Command, len(req.Commands))
+		for i, c := range req.Commands {
+			cmd, err := inPort.DecodeCommand(c.Command, c.Args)
+			if err != nil {
+				err = &inPort.BatchError{Index: i, Err: err}
+				return fail(failure(err))
+			}
+			cmds[i] = cmd
+		}
+		res, err := h.uc.DispatchBatch(cmds)
+		reply.Result = res
+		if err != nil {
+			return fail(failure(err))
+		}
+	case "viewmodel":
+		// 快照已由寫入迴圈重送
+	case "invalid":
+		return fail(http.StatusBadRequest, "bad_request", "invalid JSON message")
+	default:
+		return fail(http.StatusBadRequest, "bad_request", "unknown message type: "+req.Type)
+	}
+	return reply
+}
+
+// failure 依錯誤對照表取得錯誤回覆所需的狀態碼、錯誤碼與訊息。
+func failure(err error) (int, string, string) {
+	status, code := errorStatus(err)
+	return status, code, err.Error()
+}
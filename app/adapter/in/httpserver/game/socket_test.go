@@ -0,0 +1,131 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-ddd-architecture/internal/apitoken"
+	"go-ddd-architecture/internal/websocket"
+)
+
+func dialSocket(t *testing.T, srv *httptest.Server) *websocket.Conn {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	c, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/api/v1/game/ws", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func sendSocket(t *testing.T, c *websocket.Conn, msg string) {
+	t.Helper()
+	if err := c.WriteMessage(websocket.OpText, []byte(msg)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func readSocket(t *testing.T, c *websocket.Conn) socketMsg {
+	t.Helper()
+	_ = c.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, data, err := c.ReadMessage()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var m socketMsg
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("decode %s: %v", data, err)
+	}
+	return m
+}
+
+func TestSocket_CommandResultFollowsStatePush(t *testing.T) {
+	srv := httptest.NewServer(newTestRouter(t, RouterOptions{}))
+	defer srv.Close()
+	c := dialSocket(t, srv)
+
+	snap := readSocket(t, c)
+	if snap.Type != "snapshot" || !strings.Contains(string(snap.Data), `"CurrentTask":null`) {
+		t.Fatalf("first message should be a snapshot: %+v", snap)
+	}
+
+	sendSocket(t, c, `{"id":"a1","type":"command","command":"start-task","args":{"type":"Practice"}}`)
+	diff := readSocket(t, c)
+	if diff.Type != "diff" || diff.Version <= snap.Version || !strings.Contains(string(diff.Data), "CurrentTask") {
+		t.Fatalf("state push should precede the result: %+v", diff)
+	}
+	res := readSocket(t, c)
+	if res.Type != "result" || res.ID != "a1" || res.Version != diff.Version {
+		t.Fatalf("result should echo the id and the pushed version: %+v", res)
+	}
+
+	// 衝突錯誤沿用 HTTP 的錯誤碼（失敗也可能新增通知，故先略過推送）
+	sendSocket(t, c, `{"id":"a2","type":"command","command":"start-task","args":{"type":"Practice"}}`)
+	e := readSocket(t, c)
+	for e.Type == "diff" {
+		e = readSocket(t, c)
+	}
+	if e.Type != "error" || e.ID != "a2" || e.Error == nil || e.Error.Code != "task_already_active" {
+		t.Fatalf("expected task_already_active: %+v", e)
+	}
+
+	sendSocket(t, c, `{"id":"a3","type":"nope"}`)
+	if e := readSocket(t, c); e.Type != "error" || e.ID != "a3" || e.Error.Code != "bad_request" {
+		t.Fatalf("unknown type: %+v", e)
+	}
+}
+
+func TestSocket_PushesChangesFromOtherClients(t *testing.T) {
+	srv := httptest.NewServer(newTestRouter(t, RouterOptions{}))
+	defer srv.Close()
+	c := dialSocket(t, srv)
+	readSocket(t, c)
+
+	if resp, err := http.Post(srv.URL+"/api/v1/game/start-practice", "application/json", nil); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("start: %v %v", err, resp)
+	}
+	if m := readSocket(t, c); m.Type != "diff" || m.ID != "" {
+		t.Fatalf("expected an unsolicited diff: %+v", m)
+	}
+
+	sendSocket(t, c, `{"id":"v","type":"viewmodel"}`)
+	if m := readSocket(t, c); m.Type != "snapshot" {
+		t.Fatalf("viewmodel request should resend a snapshot: %+v", m)
+	}
+	if m := readSocket(t, c); m.Type != "result" || m.ID != "v" {
+		t.Fatalf("expected result for viewmodel: %+v", m)
+	}
+}
+
+func TestSocket_PlainRequestRejected(t *testing.T) {
+	h := newTestRouter(t, RouterOptions{})
+	if rec := serve(h, http.MethodGet, "/api/v1/game/ws"); rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "bad_request") {
+		t.Fatalf("plain GET: %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestSocket_ReadOnlyScopeCannotSendCommands(t *testing.T) {
+	h := newTestRouter(t, RouterOptions{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(apitoken.WithScope(r.Context(), apitoken.ScopeRead)))
+	}))
+	defer srv.Close()
+	c := dialSocket(t, srv)
+	readSocket(t, c)
+
+	sendSocket(t, c, `{"id":"w","type":"command","command":"start-task","args":{"type":"Practice"}}`)
+	if m := readSocket(t, c); m.Type != "error" || m.Status != http.StatusForbidden || m.Error.Code != "forbidden" {
+		t.Fatalf("read-only command: %+v", m)
+	}
+	sendSocket(t, c, `{"id":"r","type":"viewmodel"}`)
+	if m := readSocket(t, c); m.Type != "snapshot" {
+		t.Fatalf("read-only refresh should still work: %+v", m)
+	}
+}
@@ -0,0 +1,224 @@
+package httpserver
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	headerIdempotencyKey = "Idempotency-Key"
+	headerReplayed       = "Idempotent-Replayed"
+	// maxIdempotencyKeyLen 為金鑰長度上限（客戶端通常使用 UUID 或隨機十六進位字串）。
+	maxIdempotencyKeyLen = 255
+	// maxIdempotentBody 為可比對的請求本體上限；遊戲 API 的請求本體都很小。
+	maxIdempotentBody = 1 << 20
+)
+
+// 預設保留時間與數量：足以涵蓋客戶端逾時後的重試，且記憶體有上限。
+const (
+	DefaultIdempotencyTTL        = time.Hour
+	DefaultIdempotencyMaxEntries = 1000
+)
+
+// recordedResponse 為可重播的回應。
+type recordedResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// idemEntry 為一個金鑰的處理狀態；done 關閉後 resp 為最終回應（nil 表示原請求未成功記錄，可重新執行）。
+type idemEntry struct {
+	key         string
+	fingerprint [sha256.Size]byte
+	expires     time.Time
+	done        chan struct{}
+	resp        *recordedResponse
+	elem        *list.Element
+}
+
+// idempotencyStore 依建立順序保存金鑰（TTL 固定，建立順序即過期順序），超過上限時淘汰最舊者。
+type idempotencyStore struct {
+	ttl time.Duration
+	max int
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*idemEntry
+	order   *list.List
+}
+
+// begin 取得金鑰的既有紀錄；不存在時建立新紀錄並回傳 created=true，由呼叫端負責 complete 或 release。
+func (s *idempotencyStore) begin(key string, fp [sha256.Size]byte) (e *idemEntry, created bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for front := s.order.Front(); front != nil; front = s.order.Front() {
+		old := front.Value.(*idemEntry)
+		if now.Before(old.expires) && s.order.Len() < s.max {
+			break
+		}
+		s.remove(old)
+	}
+	if e, ok := s.entries[key]; ok {
+		return e, false
+	}
+	e = &idemEntry{key: key, fingerprint: fp, expires: now.Add(s.ttl), done: make(chan struct{})}
+	e.elem = s.order.PushBack(e)
+	s.entries[key] = e
+	return e, true
+}
+
+func (s *idempotencyStore) complete(e *idemEntry, resp *recordedResponse) {
+	s.mu.Lock()
+	e.resp = resp
+	s.mu.Unlock()
+	close(e.done)
+}
+
+// release 放棄紀錄（例如 5xx 或 panic），讓之後帶相同金鑰的請求重新執行。
+func (s *idempotencyStore) release(e *idemEntry) {
+	s.mu.Lock()
+	if s.entries[e.key] == e {
+		s.remove(e)
+	}
+	s.mu.Unlock()
+	close(e.done)
+}
+
+// remove 移除紀錄（呼叫端需持有 mu）；處理中的請求仍持有 e，不受影響。
+func (s *idempotencyStore) remove(e *idemEntry) {
+	s.order.Remove(e.elem)
+	if s.entries[e.key] == e {
+		delete(s.entries, e.key)
+	}
+}
+
+// NewIdempotencyMiddleware 為帶 Idempotency-Key 的 POST 提供冪等性：
+//   - 首次請求照常執行，記錄回應（5xx 不記錄，可重試）；
+//   - 之後相同客戶端、相同金鑰的請求直接重播原回應（附 Idempotent-Replayed: true），不再執行；
+//     原請求仍在處理時等待其完成；
+//   - 相同金鑰但方法、路徑或本體不同時回 422 idempotency_key_reused。
+//
+// 金鑰依客戶端身分（token 或來源 IP）區分，保留 ttl、最多 maxEntries 筆。需接在驗證之後。
+func NewIdempotencyMiddleware(ttl time.Duration, maxEntries int) Middleware {
+	return newIdempotencyMiddleware(ttl, maxEntries, time.Now)
+}
+
+func newIdempotencyMiddleware(ttl time.Duration, maxEntries int, now func() time.Time) Middleware {
+	store := &idempotencyStore{ttl: ttl, max: maxEntries, now: now, entries: map[string]*idemEntry{}, order: list.New()}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(headerIdempotencyKey)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLen {
+				writeJSONError(w, http.StatusBadRequest, "bad_request", "Idempotency-Key too long")
+				return
+			}
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBody+1))
+			if err != nil || len(body) > maxIdempotentBody {
+				writeJSONError(w, http.StatusBadRequest, "bad_request", "request body unreadable or too large")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			h := sha256.New()
+			h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+			h.Write(body)
+			var fp [sha256.Size]byte
+			h.Sum(fp[:0])
+			client, _ := clientIdentity(r)
+
+			for {
+				e, created := store.begin(client+"\x00"+key, fp)
+				if created {
+					serveRecorded(w, r, next, store, e)
+					return
+				}
+				if e.fingerprint != fp {
+					writeJSONError(w, http.StatusUnprocessableEntity, "idempotency_key_reused", "Idempotency-Key was already used for a different request")
+					return
+				}
+				select {
+				case <-e.done:
+				case <-r.Context().Done():
+					return
+				}
+				if e.resp != nil {
+					replay(w, e.resp)
+					return
+				}
+				// 原請求失敗且已釋放金鑰：以此請求重新執行
+			}
+		})
+	}
+}
+
+// serveRecorded 執行處理器並記錄回應；5xx 或 panic 時釋放金鑰。
+func serveRecorded(w http.ResponseWriter, r *http.Request, next http.Handler, store *idempotencyStore, e *idemEntry) {
+	rec := &responseCapture{ResponseWriter: w}
+	finished := false
+	defer func() {
+		if !finished {
+			store.release(e)
+		}
+	}()
+	next.ServeHTTP(rec, r)
+	finished = true
+	status := rec.statusOrDefault()
+	if status >= 500 {
+		store.release(e)
+		return
+	}
+	header := rec.Header().Clone()
+	header.Del(headerRequestID)
+	store.complete(e, &recordedResponse{status: status, header: header, body: rec.body.Bytes()})
+}
+
+func replay(w http.ResponseWriter, resp *recordedResponse) {
+	for k, v := range resp.header {
+		w.Header()[k] = v
+	}
+	w.Header().Set(headerReplayed, "true")
+	w.WriteHeader(resp.status)
+	_, _ = w.Write(resp.body)
+}
+
+// responseCapture 照常寫出回應，同時保留狀態碼與本體供重播。
+type responseCapture struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (c *responseCapture) WriteHeader(status int) {
+	if c.status == 0 {
+		c.status = status
+	}
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *responseCapture) Write(b []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	c.body.Write(b)
+	return c.ResponseWriter.Write(b)
+}
+
+// Unwrap 讓 http.ResponseController 取得底層 ResponseWriter。
+func (c *responseCapture) Unwrap() http.ResponseWriter { return c.ResponseWriter }
+
+func (c *responseCapture) statusOrDefault() int {
+	if c.status == 0 {
+		return http.StatusOK
+	}
+	return c.status
+}
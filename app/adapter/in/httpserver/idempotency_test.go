@@ -0,0 +1,100 @@
+package httpserver
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	var calls atomic.Int32
+	fail := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		if fail {
+			writeJSONError(w, http.StatusInternalServerError, "internal", "save failed")
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"call":` + strconv.Itoa(int(n)) + `,"body":"` + string(body) + `"}`))
+	})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := Chain(next, RequestIDMiddleware, newIdempotencyMiddleware(time.Minute, 2, func() time.Time { return now }))
+
+	post := func(path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(headerIdempotencyKey, key)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := post("/buy-gpu", "k1", "x")
+	again := post("/buy-gpu", "k1", "x")
+	if calls.Load() != 1 || again.Body.String() != first.Body.String() || again.Header().Get(headerReplayed) != "true" {
+		t.Fatalf("duplicate should replay: calls=%d %s vs %s", calls.Load(), first.Body, again.Body)
+	}
+	if again.Header().Get(headerRequestID) == first.Header().Get(headerRequestID) {
+		t.Fatal("replay should keep its own request id")
+	}
+	if rec := post("/buy-server", "k1", "x"); rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), "idempotency_key_reused") {
+		t.Fatalf("key reused for another request: %d %s", rec.Code, rec.Body)
+	}
+	post("/buy-gpu", "", "x")
+	post("/buy-gpu", "", "x")
+	if calls.Load() != 3 {
+		t.Fatalf("requests without a key always execute: %d", calls.Load())
+	}
+
+	// 5xx 不記錄：修正後以相同金鑰重試會重新執行
+	fail = true
+	if rec := post("/upgrade-knowledge", "k2", ""); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500: %d", rec.Code)
+	}
+	fail = false
+	if rec := post("/upgrade-knowledge", "k2", ""); rec.Code != http.StatusOK || rec.Header().Get(headerReplayed) != "" {
+		t.Fatalf("retry after 5xx should execute: %d", rec.Code)
+	}
+
+	// 過期後金鑰可再使用
+	now = now.Add(2 * time.Minute)
+	before := calls.Load()
+	post("/buy-gpu", "k1", "x")
+	if calls.Load() != before+1 {
+		t.Fatal("expired key should execute again")
+	}
+}
+
+func TestIdempotencyMiddleware_ConcurrentDuplicateWaits(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Write([]byte("done"))
+	})
+	h := NewIdempotencyMiddleware(time.Minute, 10)(next)
+	results := make(chan string, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			req := httptest.NewRequest(http.MethodPost, "/buy-gpu", nil)
+			req.Header.Set(headerIdempotencyKey, "same")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			results <- rec.Body.String()
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	if a, b := <-results, <-results; a != "done" || b != "done" || calls.Load() != 1 {
+		t.Fatalf("in-flight duplicate should wait and replay: %q %q calls=%d", a, b, calls.Load())
+	}
+}
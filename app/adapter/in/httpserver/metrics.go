@@ -0,0 +1,47 @@
+package httpserver
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-ddd-architecture/internal/metrics"
+)
+
+// PathMetrics 為 Prometheus 抓取端點。
+const PathMetrics = "/metrics"
+
+// HTTPMetrics 以路由、方法與狀態碼記錄請求數與延遲。
+type HTTPMetrics struct {
+	requests *metrics.CounterVec
+	duration *metrics.HistogramVec
+}
+
+func NewHTTPMetrics(reg *metrics.Registry) *HTTPMetrics {
+	return &HTTPMetrics{
+		requests: reg.NewCounter("http_requests_total", "HTTP requests by route, method and status.", "route", "method", "status"),
+		duration: reg.NewHistogram("http_request_duration_seconds", "HTTP request latency by route, method and status (SSE/WebSocket count the whole connection).", nil, "route", "method", "status"),
+	}
+}
+
+// Observer 回傳供 NewAccessLogMiddleware 使用的 observer。route 將請求對應到路由樣式，
+// 標籤只使用有限的路由集合：不存在的路徑記為 other，避免任意路徑造成標籤數量暴增。
+func (m *HTTPMetrics) Observer(route func(*http.Request) string) RequestObserver {
+	return func(r *http.Request, status int, d time.Duration) {
+		rt := route(r)
+		if rt == "" {
+			rt = "other"
+		}
+		labels := []string{rt, methodLabel(r.Method), strconv.Itoa(status)}
+		m.requests.Inc(labels...)
+		m.duration.Observe(d.Seconds(), labels...)
+	}
+}
+
+func methodLabel(m string) string {
+	switch m {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return m
+	}
+	return "other"
+}
@@ -0,0 +1,47 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"go-ddd-architecture/internal/metrics"
+)
+
+func TestHTTPMetrics_RecordsByRoute(t *testing.T) {
+	reg := metrics.NewRegistry()
+	route := func(r *http.Request) string {
+		if r.URL.Path == "/api/v1/game/viewmodel" {
+			return "GET /api/v1/game/viewmodel"
+		}
+		return ""
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route(r) == "" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	h := NewAccessLogMiddleware(zap.NewNop(), NewHTTPMetrics(reg).Observer(route))(next)
+	for _, p := range []string{"/api/v1/game/viewmodel", "/api/v1/game/viewmodel", "/random/1", "/random/2"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, p, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, PathMetrics, nil))
+	out := rec.Body.String()
+	for _, want := range []string{
+		`http_requests_total{route="GET /api/v1/game/viewmodel",method="GET",status="200"} 2`,
+		`http_requests_total{route="other",method="GET",status="404"} 2`,
+		`http_request_duration_seconds_count{route="other",method="GET",status="404"} 2`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != metrics.ContentType {
+		t.Fatalf("content type %q", ct)
+	}
+}
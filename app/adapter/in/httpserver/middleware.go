@@ -62,8 +62,11 @@ func NewRecoveryMiddleware(log *zap.Logger) Middleware {
 	}
 }
 
-// AccessLogMiddleware 記錄請求摘要（方法、路徑、狀態碼、耗時、request id）。
-func NewAccessLogMiddleware(log *zap.Logger) Middleware {
+// RequestObserver 於每個請求結束後收到狀態碼與耗時（例如記錄 metrics）。
+type RequestObserver func(r *http.Request, status int, d time.Duration)
+
+// AccessLogMiddleware 記錄請求摘要（方法、路徑、狀態碼、耗時、request id），並轉給 observers。
+func NewAccessLogMiddleware(log *zap.Logger, observers ...RequestObserver) Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
@@ -78,6 +81,9 @@ func NewAccessLogMiddleware(log *zap.Logger) Middleware {
 				zap.Duration("duration", dur),
 				zap.String("rid", rid),
 			)
+			for _, o := range observers {
+				o(r, sr.statusOrDefault(), dur)
+			}
 		})
 	}
 }
@@ -94,6 +100,9 @@ func (s *statusRecorder) WriteHeader(statusCode int) {
 	s.ResponseWriter.WriteHeader(statusCode)
 }
 
+// Unwrap 讓 http.ResponseController 取得底層 ResponseWriter（SSE 需要 Flush）。
+func (s *statusRecorder) Unwrap() http.ResponseWriter { return s.ResponseWriter }
+
 func (s *statusRecorder) statusOrDefault() int {
 	if s.status == 0 {
 		return http.StatusOK
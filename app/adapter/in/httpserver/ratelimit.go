@@ -0,0 +1,173 @@
+package httpserver
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	gameModule "go-ddd-architecture/app/adapter/in/httpserver/game"
+	"go-ddd-architecture/internal/apitoken"
+	"go-ddd-architecture/internal/websocket"
+)
+
+// Limit 為 token bucket 預算：每秒補充 Rate 個、最多累積 Burst 個；Rate <= 0 表示不限制。
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// RouteLimit 為特定路由的預算；Path 比對請求路徑的結尾（同時涵蓋 /api/v1/game 與相容前綴），
+// Method 為空表示不限方法。
+type RouteLimit struct {
+	Method string
+	Path   string
+	Limit  Limit
+}
+
+// RateLimitConfig 為速率限制設定：先找第一個符合的 Routes，找不到時使用 Default。
+// 每個客戶端（token 或來源 IP）對每組預算各有一個桶。
+type RateLimitConfig struct {
+	Default Limit
+	Routes  []RouteLimit
+}
+
+// DefaultRateLimits 回傳預設預算：一般請求寬鬆（客戶端輪詢每秒數次），
+// 所有改變狀態的 POST（寫入存檔的動作、通知確認、開發模式的時鐘控制）較嚴格。
+func DefaultRateLimits() RateLimitConfig {
+	action := Limit{Rate: 5, Burst: 10}
+	cfg := RateLimitConfig{Default: Limit{Rate: 20, Burst: 40}}
+	for _, p := range []string{
+		"/try-finish", "/claim-offline",
+		"/start-practice", "/start-targeted", "/start-deploy", "/start-research",
+		"/upgrade-knowledge", "/select-language", "/buy-server", "/buy-gpu", "/enqueue", "/automation",
+		"/notices/ack", "/commands", "/commands/batch",
+		"/clock/advance", "/clock/set", "/clock/scale", "/clock/reset",
+	} {
+		cfg.Routes = append(cfg.Routes, RouteLimit{Method: http.MethodPost, Path: p, Limit: action})
+	}
+	return cfg
+}
+
+// bucketIdle 為桶閒置多久後可回收（此時必已補滿，回收不影響限制結果）。
+const bucketIdle = 10 * time.Minute
+
+type bucketKey struct {
+	client string
+	group  int // Routes 的索引；-1 為 Default
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter 保存所有桶；定期回收閒置的桶，記憶體隨活躍客戶端數量而定。
+type rateLimiter struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*bucket
+	lastSweep time.Time
+}
+
+// allow 從桶中取出一個 token；不足時回傳需等待的時間。
+func (l *rateLimiter) allow(key bucketKey, lim Limit) (bool, time.Duration) {
+	if lim.Rate <= 0 {
+		return true, 0
+	}
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.lastSweep) >= bucketIdle {
+		for k, b := range l.buckets {
+			if now.Sub(b.last) >= bucketIdle {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(lim.Burst), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(lim.Burst), b.tokens+now.Sub(b.last).Seconds()*lim.Rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / lim.Rate * float64(time.Second))
+}
+
+func (c RateLimitConfig) match(method, path string) (int, Limit) {
+	for i, rl := range c.Routes {
+		if (rl.Method == "" || rl.Method == method) && strings.HasSuffix(path, rl.Path) {
+			return i, rl.Limit
+		}
+	}
+	return -1, c.Default
+}
+
+// NewRateLimitMiddleware 依客戶端身分限制請求速率，超出預算回 429 rate_limited 與 Retry-After（秒）。
+// 需接在驗證之後：已驗證的請求以 token 區分客戶端，其餘（未啟用驗證）以來源 IP 區分。
+// 被拒絕的請求以 Warn 記錄（含 request id），不記錄 token 本身。
+// WebSocket 握手另帶 game.MessageLimiter：連線內的每則命令以對應的 HTTP 路由扣除同一客戶端的同一組預算。
+func NewRateLimitMiddleware(log *zap.Logger, cfg RateLimitConfig) Middleware {
+	return newRateLimitMiddleware(log, cfg, time.Now)
+}
+
+func newRateLimitMiddleware(log *zap.Logger, cfg RateLimitConfig, now func() time.Time) Middleware {
+	l := &rateLimiter{now: now, buckets: map[bucketKey]*bucket{}, lastSweep: now()}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client, label := clientIdentity(r)
+			rid, _ := r.Context().Value(ctxKeyRequestID).(string)
+			allow := func(method, path string) (bool, time.Duration) {
+				group, lim := cfg.match(method, path)
+				ok, wait := l.allow(bucketKey{client: client, group: group}, lim)
+				if !ok {
+					log.Warn("rate_limited",
+						zap.String("method", method),
+						zap.String("path", path),
+						zap.String("client", label),
+						zap.Int("retry_after", retryAfterSecs(wait)),
+						zap.String("rid", rid),
+					)
+				}
+				return ok, wait
+			}
+			ok, wait := allow(r.Method, r.URL.Path)
+			if ok {
+				if websocket.IsUpgrade(r) {
+					r = r.WithContext(gameModule.WithMessageLimiter(r.Context(), allow))
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+			secs := retryAfterSecs(wait)
+			w.Header().Set("Retry-After", strconv.Itoa(secs))
+			writeJSONError(w, http.StatusTooManyRequests, "rate_limited", "too many requests; retry after "+strconv.Itoa(secs)+"s")
+		})
+	}
+}
+
+func retryAfterSecs(wait time.Duration) int { return int(math.Ceil(wait.Seconds())) }
+
+// clientIdentity 回傳限流用的鍵與可記錄的標籤（token 只以範圍表示）。
+func clientIdentity(r *http.Request) (key, label string) {
+	if scope, ok := apitoken.ScopeFrom(r.Context()); ok {
+		return "token:" + bearerToken(r), "token:" + string(scope)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host, "ip:" + host
+}
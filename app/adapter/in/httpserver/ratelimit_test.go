@@ -0,0 +1,143 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	gameModule "go-ddd-architecture/app/adapter/in/httpserver/game"
+	"go-ddd-architecture/app/domain/gametime"
+	"go-ddd-architecture/app/infra/clock"
+	"go-ddd-architecture/app/infra/memory"
+	usecase "go-ddd-architecture/app/usecase/game"
+	"go-ddd-architecture/internal/apitoken"
+	"go-ddd-architecture/internal/websocket"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	core, logs := observer.New(zap.WarnLevel)
+	cfg := RateLimitConfig{
+		Default: Limit{Rate: 100, Burst: 100},
+		Routes:  []RouteLimit{{Method: http.MethodPost, Path: "/try-finish", Limit: Limit{Rate: 1, Burst: 2}}},
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	h := Chain(ok, RequestIDMiddleware, newRateLimitMiddleware(zap.New(core), cfg, func() time.Time { return now }))
+
+	do := func(method, path, addr string, scope apitoken.Scope, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = addr
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+			req = req.WithContext(apitoken.WithScope(req.Context(), scope))
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := do(http.MethodPost, "/api/v1/game/try-finish", "10.0.0.1:1", "", ""); rec.Code != http.StatusNoContent {
+			t.Fatalf("request %d within burst: %d", i, rec.Code)
+		}
+	}
+	rec := do(http.MethodPost, "/api/game/try-finish", "10.0.0.1:2", "", "")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "1" || !strings.Contains(rec.Body.String(), "rate_limited") {
+		t.Fatalf("over budget (legacy prefix shares the bucket): %d %q %s", rec.Code, rec.Header().Get("Retry-After"), rec.Body)
+	}
+	if e := logs.All(); len(e) != 1 || e[0].ContextMap()["rid"] == "" || e[0].ContextMap()["client"] != "ip:10.0.0.1" {
+		t.Fatalf("rejection should be logged with the request id: %+v", e)
+	}
+
+	// 其他路由、其他客戶端各自計算
+	if rec := do(http.MethodGet, "/api/v1/game/viewmodel", "10.0.0.1:1", "", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("default budget is separate: %d", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/api/v1/game/try-finish", "10.0.0.2:1", "", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("other IP has its own bucket: %d", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/api/v1/game/try-finish", "10.0.0.1:1", apitoken.ScopeAdmin, "tok"); rec.Code != http.StatusNoContent {
+		t.Fatalf("authenticated client is keyed by token: %d", rec.Code)
+	}
+
+	now = now.Add(time.Second)
+	if rec := do(http.MethodPost, "/api/v1/game/try-finish", "10.0.0.1:1", "", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("bucket should refill: %d", rec.Code)
+	}
+}
+
+// WebSocket 內的命令與 POST /commands 共用同一組預算，超出時回 type=error、status 429。
+func TestRateLimitMiddleware_SocketCommands(t *testing.T) {
+	clk := clock.SystemClock{}
+	uc := usecase.NewInteractor(memory.NewInMemoryRepo(), clk, gametime.NewOfflineCalculator())
+	if err := uc.Initialize(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	hist := usecase.NewHistoryService(uc, memory.NewInMemoryHistory(), clk)
+	router := gameModule.NewRouter(gameModule.NewHandler(uc, hist, clk, zap.NewNop()), nil, gameModule.RouterOptions{}).Handler()
+	cfg := RateLimitConfig{
+		Default: Limit{Rate: 100, Burst: 100},
+		Routes:  []RouteLimit{{Method: http.MethodPost, Path: "/commands", Limit: Limit{Rate: 0.001, Burst: 2}}},
+	}
+	srv := httptest.NewServer(Chain(router, NewRateLimitMiddleware(zap.NewNop(), cfg)))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	conn, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/api/v1/game/ws", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	statuses := map[string]int{}
+	for i := 0; i < 3; i++ {
+		msg := fmt.Sprintf(`{"id":"c%d","type":"command","command":"set-automation","args":{"enabled":true}}`, i)
+		if err := conn.WriteMessage(websocket.OpText, []byte(msg)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	for len(statuses) < 3 {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		var m struct {
+			ID     string
+			Type   string
+			Status int
+		}
+		_ = json.Unmarshal(data, &m)
+		if m.ID != "" {
+			statuses[m.ID] = m.Status
+		}
+	}
+	if statuses["c0"] != 0 || statuses["c1"] != 0 || statuses["c2"] != http.StatusTooManyRequests {
+		t.Fatalf("third socket command should be rate limited: %v", statuses)
+	}
+}
+
+// 每個改變狀態的 POST 路由都應落在嚴格的動作預算，而非寬鬆的預設預算。
+func TestDefaultRateLimits_CoverStateChangingRoutes(t *testing.T) {
+	cfg := DefaultRateLimits()
+	var h *gameModule.Handler
+	var dev *gameModule.DevHandler
+	routes := append(h.Routes(), dev.Routes()...)
+	for _, rt := range routes {
+		if rt.Method != http.MethodPost {
+			continue
+		}
+		if group, _ := cfg.match(rt.Method, gameModule.PrefixV1+rt.Path); group < 0 {
+			t.Errorf("POST %s falls back to the default budget", rt.Path)
+		}
+	}
+}
@@ -6,26 +6,49 @@ import (
 	"go.uber.org/zap"
 
 	gameModule "go-ddd-architecture/app/adapter/in/httpserver/game"
+	"go-ddd-architecture/internal/metrics"
 )
 
 type Router struct {
-	mux *http.ServeMux
+	mux  *http.ServeMux
+	game *gameModule.Router
+	// metrics 為 nil 時不記錄也不提供 /metrics
+	metrics *HTTPMetrics
 }
 
-func NewRouter(gameRouter *gameModule.Router) *Router {
+// NewRouter 聚合各模組路由；reg 非 nil 時掛載 /metrics 並記錄每個路由的請求數與延遲。
+func NewRouter(gameRouter *gameModule.Router, reg *metrics.Registry) *Router {
 	mux := http.NewServeMux()
 	// 掛載 game 模組（它已註冊自身路由於 mux 上）
 	// 這裡也可改為子路由分發邏輯，現階段直接使用其 mux
 	// 為了統一出口 middleware，我們把子路由的 Handler 掛到一個前綴下
 	mux.Handle("/", gameRouter.Handler())
-	return &Router{mux: mux}
+	r := &Router{mux: mux, game: gameRouter}
+	if reg != nil {
+		mux.Handle(http.MethodGet+" "+PathMetrics, reg.Handler())
+		r.metrics = NewHTTPMetrics(reg)
+	}
+	return r
 }
 
-// 包上預設 middleware 鏈
-func (r *Router) HandlerWithLogger(log *zap.Logger) http.Handler {
-	return Chain(r.mux,
+// route 回傳請求對應的路由樣式，供 metrics 標籤使用。
+func (r *Router) route(req *http.Request) string {
+	if _, pattern := r.mux.Handler(req); pattern != "/" {
+		return pattern
+	}
+	return r.game.Route(req)
+}
+
+// 包上預設 middleware 鏈；extra（例如驗證）接在存取日誌之後，被拒絕的請求仍會記錄。
+func (r *Router) HandlerWithLogger(log *zap.Logger, extra ...Middleware) http.Handler {
+	var observers []RequestObserver
+	if r.metrics != nil {
+		observers = append(observers, r.metrics.Observer(r.route))
+	}
+	mws := append([]Middleware{
 		NewRecoveryMiddleware(log),
 		RequestIDMiddleware,
-		NewAccessLogMiddleware(log),
-	)
+		NewAccessLogMiddleware(log, observers...),
+	}, extra...)
+	return Chain(r.mux, mws...)
 }
@@ -2,6 +2,7 @@ package httpserver
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"time"
 )
@@ -11,7 +12,15 @@ type Server struct {
 }
 
 func NewServer(addr string, handler http.Handler) *Server {
-	s := &http.Server{Addr: addr, Handler: handler, ReadHeaderTimeout: 5 * time.Second}
+	// 長連線（SSE）不會自行結束：關機時取消所有請求的 context，讓串流迅速返回，Shutdown 才不會等到逾時
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	s.RegisterOnShutdown(cancel)
 	return &Server{httpServer: s}
 }
 
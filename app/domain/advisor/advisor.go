@@ -0,0 +1,247 @@
+// Package advisor 依目前玩家狀態與平衡規則預測進度，並以投資報酬率排序可行動作。
+package advisor
+
+import (
+	"errors"
+	"sort"
+
+	"go-ddd-architecture/app/domain/player"
+)
+
+// Kind 為可建議的動作類型。
+type Kind string
+
+const (
+	Upgrade   Kind = "upgrade"
+	BuyServer Kind = "buy-server"
+	BuyGPU    Kind = "buy-gpu"
+	Switch    Kind = "switch-language"
+)
+
+// ErrUnknownAction 動作類型不存在。
+var ErrUnknownAction = errors.New("unknown action")
+
+// Action 為一個可執行的動作；Lang 僅用於 Switch。
+type Action struct {
+	Kind Kind
+	Lang string
+}
+
+// Apply 以領域規則執行動作（資源不足等情況回傳對應的領域錯誤）。
+func (a Action) Apply(p *player.Player) error {
+	switch a.Kind {
+	case Upgrade:
+		return p.UpgradeKnowledge()
+	case BuyServer:
+		return p.BuyServer()
+	case BuyGPU:
+		return p.BuyGPU()
+	case Switch:
+		return p.SelectLanguage(a.Lang)
+	}
+	return ErrUnknownAction
+}
+
+// Rates 為目前語言的每分鐘期望收入。
+type Rates struct {
+	KnowledgePerMin float64
+	ResearchPerMin  float64
+}
+
+// IncomeRates 估算每分鐘期望收入：被動產出，加上持續進行期望 Knowledge 最高的任務（已乘上成功率）。
+func IncomeRates(p player.Player) Rates {
+	q := p.Clone() // Explain 可能初始化 Skills，不影響呼叫端
+	r := Rates{KnowledgePerMin: float64(q.KnowledgePerMinute()), ResearchPerMin: float64(q.ResearchPerMinute())}
+	var bestK, bestR float64
+	for _, tt := range player.TaskTypes {
+		e, err := q.Explain(tt)
+		if err != nil || e.Duration <= 0 {
+			continue
+		}
+		perMin := 60 / e.Duration.Seconds()
+		if k := e.ExpectedReward * perMin; k > bestK {
+			bestK, bestR = k, e.ExpectedResearch*perMin
+		}
+	}
+	r.KnowledgePerMin += bestK
+	r.ResearchPerMin += bestR
+	return r
+}
+
+// ItemForecast 為商店品項的負擔預測。
+type ItemForecast struct {
+	Kind          Kind
+	CostKnowledge int64
+	// Minutes 為以目前收入存到所需 Knowledge 的分鐘數（已負擔得起為 0；無法預測為 -1）
+	Minutes float64
+	// Blocked 非空表示目前無法購買（例如沒有空插槽）
+	Blocked string
+}
+
+// Forecast 為目前語言的進度預測。
+type Forecast struct {
+	Language       string
+	Rates          Rates
+	UpgradeCost    int64
+	UpgradeMinutes float64
+	Store          []ItemForecast
+}
+
+// Advice 為單一動作的評估。
+type Advice struct {
+	Action        Action
+	CostKnowledge int64
+	CostResearch  int64
+	Affordable    bool
+	// WaitMinutes 為存到成本所需的分鐘數（已負擔得起為 0；無法預測為 -1）
+	WaitMinutes float64
+	Blocked     string
+	// 執行後每分鐘期望收入的變化
+	DeltaKnowledgePerMin float64
+	DeltaResearchPerMin  float64
+	// 每花費 1 點成本帶來的每分鐘收入增加
+	KnowledgeROI float64
+	ResearchROI  float64
+	// Score 為排序依據：收入相對成長 ÷ 成本折合的收入分鐘數（越高越好）
+	Score float64
+	Note  string
+}
+
+// Report 為預測與排序後的建議（最佳在前）。
+type Report struct {
+	Forecast        Forecast
+	Recommendations []Advice
+}
+
+// Advise 產生預測與建議。
+func Advise(p player.Player) Report {
+	p = p.Clone()
+	if p.CurrentLanguage == "" {
+		p.CurrentLanguage = "go"
+	}
+	if p.Skills == nil {
+		p.Skills = map[string]player.Skill{}
+	}
+	base := IncomeRates(p)
+	s := p.Skills[p.CurrentLanguage]
+	freeSlot := p.GPUs < p.Servers*player.SlotsPerServer
+
+	f := Forecast{Language: p.CurrentLanguage, Rates: base, UpgradeCost: p.NextUpgradeCost()}
+	f.UpgradeMinutes = minutesTo(f.UpgradeCost-s.Research, base.ResearchPerMin)
+	f.Store = []ItemForecast{
+		{Kind: BuyServer, CostKnowledge: player.ServerCostK, Minutes: minutesTo(player.ServerCostK-s.Knowledge, base.KnowledgePerMin)},
+		{Kind: BuyGPU, CostKnowledge: player.GPUCostK, Minutes: minutesTo(player.GPUCostK-s.Knowledge, base.KnowledgePerMin)},
+	}
+	if !freeSlot {
+		f.Store[1].Blocked = "no free GPU slot"
+	}
+
+	var out []Advice
+	// 升級：花費 Research，提升被動產出與任務獎勵
+	up := evaluate(p, base, func(q *player.Player) {
+		qs := q.Skills[q.CurrentLanguage]
+		qs.Level++
+		q.Skills[q.CurrentLanguage] = qs
+	})
+	up.Action = Action{Kind: Upgrade}
+	up.CostResearch = f.UpgradeCost
+	up.WaitMinutes = f.UpgradeMinutes
+	out = append(out, up)
+
+	// 顯卡：花費 Knowledge，提升 Research 產率與任務的 Research 擲骰
+	gpu := evaluate(p, base, func(q *player.Player) { q.GPUs++ })
+	gpu.Action = Action{Kind: BuyGPU}
+	gpu.CostKnowledge = player.GPUCostK
+	gpu.WaitMinutes = f.Store[1].Minutes
+	gpu.Blocked = f.Store[1].Blocked
+	out = append(out, gpu)
+
+	// 伺服器本身不增加收入；沒有空插槽時以「伺服器 + 顯卡」的組合評估
+	srv := Advice{Action: Action{Kind: BuyServer}, CostKnowledge: player.ServerCostK, WaitMinutes: f.Store[0].Minutes}
+	if !freeSlot {
+		srv.DeltaKnowledgePerMin, srv.DeltaResearchPerMin = gpu.DeltaKnowledgePerMin, gpu.DeltaResearchPerMin
+		srv.Note = "enables a GPU (valued as server + GPU)"
+	} else {
+		srv.Note = "free GPU slots available"
+	}
+	out = append(out, srv)
+
+	// 切換語言：各語言進度獨立，切換至等級/Knowledge 較高的語言可能提高收入
+	for _, lang := range player.UnlockedLanguages {
+		if lang == p.CurrentLanguage {
+			continue
+		}
+		sw := evaluate(p, base, func(q *player.Player) { _ = q.SelectLanguage(lang) })
+		sw.Action = Action{Kind: Switch, Lang: lang}
+		sw.Note = "spending uses the selected language's pools"
+		out = append(out, sw)
+	}
+
+	for i := range out {
+		score(&out[i], s, base, !freeSlot)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if (out[i].Blocked == "") != (out[j].Blocked == "") {
+			return out[i].Blocked == ""
+		}
+		return out[i].Score > out[j].Score
+	})
+	return Report{Forecast: f, Recommendations: out}
+}
+
+// evaluate 於副本上套用變更（不計成本），回傳收入變化。
+func evaluate(p player.Player, base Rates, change func(q *player.Player)) Advice {
+	q := p.Clone()
+	change(&q)
+	after := IncomeRates(q)
+	return Advice{
+		DeltaKnowledgePerMin: after.KnowledgePerMin - base.KnowledgePerMin,
+		DeltaResearchPerMin:  after.ResearchPerMin - base.ResearchPerMin,
+	}
+}
+
+// score 計算可負擔性、ROI 與排序分數。
+// 成本以「需花費幾分鐘的收入」計（至少 1 分鐘）；伺服器在無空插槽時加計一張顯卡的成本。
+func score(a *Advice, s player.Skill, base Rates, serverNeedsGPU bool) {
+	a.Affordable = a.Blocked == "" && s.Knowledge >= a.CostKnowledge && s.Research >= a.CostResearch
+	if a.CostKnowledge > 0 {
+		a.KnowledgeROI = a.DeltaKnowledgePerMin / float64(a.CostKnowledge)
+		a.ResearchROI = a.DeltaResearchPerMin / float64(a.CostKnowledge)
+	} else if a.CostResearch > 0 {
+		a.KnowledgeROI = a.DeltaKnowledgePerMin / float64(a.CostResearch)
+		a.ResearchROI = a.DeltaResearchPerMin / float64(a.CostResearch)
+	}
+	costK := float64(a.CostKnowledge)
+	if a.Action.Kind == BuyServer && serverNeedsGPU {
+		costK += float64(player.GPUCostK)
+	}
+	minutes := 0.0
+	if base.KnowledgePerMin > 0 {
+		minutes += costK / base.KnowledgePerMin
+	}
+	if base.ResearchPerMin > 0 {
+		minutes += float64(a.CostResearch) / base.ResearchPerMin
+	}
+	if minutes < 1 {
+		minutes = 1
+	}
+	growth := 0.0
+	if base.KnowledgePerMin > 0 {
+		growth += a.DeltaKnowledgePerMin / base.KnowledgePerMin
+	}
+	if base.ResearchPerMin > 0 {
+		growth += a.DeltaResearchPerMin / base.ResearchPerMin
+	}
+	a.Score = growth / minutes
+}
+
+// minutesTo 回傳以 rate 每分鐘累積 deficit 所需的分鐘數；已足夠回傳 0，無法累積回傳 -1。
+func minutesTo(deficit int64, rate float64) float64 {
+	if deficit <= 0 {
+		return 0
+	}
+	if rate <= 0 {
+		return -1
+	}
+	return float64(deficit) / rate
+}
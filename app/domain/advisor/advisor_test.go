@@ -0,0 +1,51 @@
+package advisor
+
+import (
+	"errors"
+	"testing"
+
+	"go-ddd-architecture/app/domain/player"
+)
+
+func TestAdvise_ForecastAndRanking(t *testing.T) {
+	p := player.Player{
+		CurrentLanguage: "go",
+		Servers:         1,
+		Skills: map[string]player.Skill{
+			"go": {Knowledge: 100, Research: 40},
+			"py": {Level: 4},
+		},
+	}
+	r := Advise(p)
+
+	if r.Forecast.UpgradeCost != 100 || r.Forecast.UpgradeMinutes <= 0 {
+		t.Fatalf("unexpected upgrade forecast: %+v", r.Forecast)
+	}
+	if gpu := r.Forecast.Store[1]; gpu.Kind != BuyGPU || gpu.Minutes != 0 || gpu.Blocked != "" {
+		t.Fatalf("gpu should be affordable now: %+v", gpu)
+	}
+	if srv := r.Forecast.Store[0]; srv.Minutes <= 0 {
+		t.Fatalf("server should need more knowledge: %+v", srv)
+	}
+	// 高等級的 py 明顯提高收入，應排第一
+	if top := r.Recommendations[0]; top.Action != (Action{Kind: Switch, Lang: "py"}) || top.Score <= 0 {
+		t.Fatalf("expected switch to py first, got %+v", top)
+	}
+	for _, a := range r.Recommendations {
+		if a.Action.Kind == Switch && a.Action.Lang == "js" && a.Score >= 0 {
+			t.Fatalf("switching to an untrained language should not help: %+v", a)
+		}
+	}
+
+	a, ok := GreedyROI.Next(p)
+	if !ok || a.Apply(&p) != nil || p.CurrentLanguage != "py" {
+		t.Fatalf("greedy policy should switch to py: %+v ok=%v", a, ok)
+	}
+}
+
+func TestAction_ApplyUnknownKind(t *testing.T) {
+	p := player.Player{CurrentLanguage: "go"}
+	if err := (Action{Kind: "teleport"}).Apply(&p); !errors.Is(err, ErrUnknownAction) {
+		t.Fatalf("unknown action kind: %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package advisor
+
+import (
+	"sort"
+
+	"go-ddd-architecture/app/domain/player"
+)
+
+// Policy 決定下一個要執行的動作；ok=false 表示此刻不行動（等待資源）。
+type Policy interface {
+	Next(p player.Player) (Action, bool)
+}
+
+// PolicyFunc 讓一般函式實作 Policy。
+type PolicyFunc func(p player.Player) (Action, bool)
+
+func (f PolicyFunc) Next(p player.Player) (Action, bool) { return f(p) }
+
+// GreedyROI 執行排名最高且有正收益的建議；負擔不起時等待，而非改做次佳動作。
+var GreedyROI Policy = PolicyFunc(func(p player.Player) (Action, bool) {
+	for _, a := range Advise(p).Recommendations {
+		if a.Blocked != "" || a.Score <= 0 {
+			continue
+		}
+		return a.Action, a.Affordable
+	}
+	return Action{}, false
+})
+
+// GreedyUpgrade 只要研究點足夠就升級當前語言；不購買硬體。
+var GreedyUpgrade Policy = PolicyFunc(func(p player.Player) (Action, bool) {
+	s := p.Skills[p.CurrentLanguage]
+	return Action{Kind: Upgrade}, s.Research >= p.NextUpgradeCost()
+})
+
+// HardwareFirst 優先把 Knowledge 花在硬體：有空插槽買顯卡、否則買伺服器，兩者都買不起時才考慮升級。
+var HardwareFirst Policy = PolicyFunc(func(p player.Player) (Action, bool) {
+	s := p.Skills[p.CurrentLanguage]
+	switch {
+	case p.GPUs < p.Servers*player.SlotsPerServer && s.Knowledge >= player.GPUCostK:
+		return Action{Kind: BuyGPU}, true
+	case p.GPUs >= p.Servers*player.SlotsPerServer && s.Knowledge >= player.ServerCostK:
+		return Action{Kind: BuyServer}, true
+	}
+	return GreedyUpgrade.Next(p)
+})
+
+// Idle 從不行動，作為比較基準。
+var Idle Policy = PolicyFunc(func(player.Player) (Action, bool) { return Action{}, false })
+
+// Policies 為可依名稱選用的策略（simulate 指令使用）。
+var Policies = map[string]Policy{
+	"roi":            GreedyROI,
+	"greedy-upgrade": GreedyUpgrade,
+	"hardware-first": HardwareFirst,
+	"idle":           Idle,
+}
+
+// PolicyNames 回傳排序後的策略名稱。
+func PolicyNames() []string {
+	names := make([]string, 0, len(Policies))
+	for n := range Policies {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
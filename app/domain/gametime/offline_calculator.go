@@ -22,21 +22,16 @@ type OfflineResult struct {
 	ClampedTo8h     bool
 	AnomalyDetected bool
 	Message         string
+	// Finished 為離線期間完成並結算的任務。
+	Finished []player.TaskOutcome
 }
 
 // OfflineCalculator 根據關閉時與現在的時間，計算離線收益。
-// MVP：以固定產率做簡化，後續再接入語言/等級影響。
-type OfflineCalculator struct {
-	// base per-minute gains as MVP constants
-	knowledgePerMinute int64
-	researchPerMinute  int64
-}
+// 產率與任務結算皆由 Player.Advance 處理，與線上推進共用同一路徑；此處只負責時間校驗與封頂。
+type OfflineCalculator struct{}
 
 func NewOfflineCalculator() *OfflineCalculator {
-	return &OfflineCalculator{
-		knowledgePerMinute: 10, // MVP 常數，可調整
-		researchPerMinute:  2,
-	}
+	return &OfflineCalculator{}
 }
 
 // Compute 計算並應用到玩家（不直接持久化）。
@@ -58,31 +53,14 @@ func (c *OfflineCalculator) Compute(p *player.Player, ts Timestamps, now time.Ti
 	// 簡化：忽略單調代理值的精細比對，先做 MVP：若代理值為 0，跳過檢查。
 	// 後續可加入期望差值門檻，嚴重不符改為 0 或數分鐘上限。
 
-	minutes := int64(dt / time.Minute)
-	if minutes <= 0 {
-		p.LastSeen = now
-		return OfflineResult{}
-	}
-
-	// 若玩家有等級，依玩家產率計算；否則用常數
-	kpm := c.knowledgePerMinute
-	rpm := c.researchPerMinute
-	if p != nil {
-		if v := p.KnowledgePerMinute(); v > 0 {
-			kpm = v
-		}
-		if v := p.ResearchPerMinute(); v > 0 {
-			rpm = v
-		}
-	}
-	gk := minutes * kpm
-	gr := minutes * rpm
-
-	p.ApplyOfflineGains(gk, gr, now)
+	adv := p.Advance(ts.WallClockAtClose, dt)
+	// 封頂後超出的時間直接略過
+	p.LastSeen = now
 
 	return OfflineResult{
-		GainedKnowledge: gk,
-		GainedResearch:  gr,
+		GainedKnowledge: adv.GainedKnowledge,
+		GainedResearch:  adv.GainedResearch,
 		ClampedTo8h:     clamped,
+		Finished:        adv.Finished,
 	}
 }
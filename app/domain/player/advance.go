@@ -0,0 +1,74 @@
+package player
+
+import (
+	"time"
+
+	"go-ddd-architecture/app/domain/task"
+)
+
+// TaskOutcome 描述一個在推進時間時結算的任務。
+type TaskOutcome struct {
+	TaskID   string
+	Type     task.Type
+	Language string
+	Success  bool
+	Reward   int64
+	DoneAt   time.Time
+}
+
+// AdvanceResult 為一次時間推進的結果。
+type AdvanceResult struct {
+	GainedKnowledge int64
+	GainedResearch  int64
+	Finished        []TaskOutcome
+}
+
+// maxChain 限制單次推進內連續結算的任務數，避免異常時長造成無限迴圈。
+const maxChain = 20000
+
+// Advance 將所有與時間相關的狀態自 from 推進 dt，並把 LastSeen 設為 from+dt。
+// 線上請求與離線結算共用此路徑；結果只取決於輸入狀態與時間區間（可重播）：
+//   - 任務：完成時間落在區間內者，以其完成時間結算（成功判定的亂數種子即為完成時間）；
+//     並於同一時間點接續佇列或自動 Practice，區間內可連續結算多個。
+//   - 被動產出：以當前語言的每分鐘產率，按整分鐘累計，不足一分鐘的部分留在 ProductionCarry。
+//
+// 未來的佇列、增益效果與維護成本等時間相關規則也應在此推進。
+func (p *Player) Advance(from time.Time, dt time.Duration) AdvanceResult {
+	var res AdvanceResult
+	if dt <= 0 {
+		return res
+	}
+	to := from.Add(dt)
+	res.Finished = p.SettleTasks(to)
+
+	p.ProductionCarry += dt
+	minutes := int64(p.ProductionCarry / time.Minute)
+	if minutes > 0 {
+		p.ProductionCarry -= time.Duration(minutes) * time.Minute
+		res.GainedKnowledge = minutes * p.KnowledgePerMinute()
+		res.GainedResearch = minutes * p.ResearchPerMinute()
+	}
+	p.ApplyOfflineGains(res.GainedKnowledge, res.GainedResearch, to)
+	return res
+}
+
+// SettleTasks 結算完成時間不晚於 to 的任務（各以其完成時間結算），並於同一時間點接續佇列或自動 Practice，
+// 可連續結算多個。不推進 LastSeen 與被動產出；時鐘落後 LastSeen 時，以時鐘時間開始的任務仍可藉此結算。
+func (p *Player) SettleTasks(to time.Time) []TaskOutcome {
+	var finished []TaskOutcome
+	for i := 0; i < maxChain && p.Current != nil && p.Current.IsActive() && p.Current.Done(to); i++ {
+		t := p.Current
+		doneAt := t.DoneAt()
+		_, reward := p.TryFinish(doneAt)
+		finished = append(finished, TaskOutcome{
+			TaskID:   t.ID,
+			Type:     t.Type,
+			Language: t.Language,
+			Success:  reward > 0,
+			Reward:   reward,
+			DoneAt:   doneAt,
+		})
+		p.StartNext(doneAt)
+	}
+	return finished
+}
@@ -0,0 +1,21 @@
+package player
+
+import "errors"
+
+// 領域錯誤：由用例原樣傳遞，Adapter 依錯誤類型對應狀態碼與穩定錯誤碼。
+var (
+	// ErrTaskAlreadyActive 已有進行中的任務，無法再開始新任務。
+	ErrTaskAlreadyActive = errors.New("task already active")
+	// ErrUnknownTaskType 任務類型不存在。
+	ErrUnknownTaskType = errors.New("unknown task type")
+	// ErrQueueFull 任務佇列已達上限。
+	ErrQueueFull = errors.New("task queue is full")
+	// ErrInsufficientKnowledge 當前語言的 Knowledge 不足以購買。
+	ErrInsufficientKnowledge = errors.New("not enough knowledge")
+	// ErrInsufficientResearch 當前語言的 Research 不足以升級。
+	ErrInsufficientResearch = errors.New("not enough research")
+	// ErrNoFreeSlot 沒有可安裝顯卡的伺服器插槽。
+	ErrNoFreeSlot = errors.New("no free GPU slot")
+	// ErrLanguageLocked 語言尚未開放。
+	ErrLanguageLocked = errors.New("language is locked")
+)
@@ -0,0 +1,152 @@
+package player
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"go-ddd-architecture/app/domain/task"
+)
+
+// taskSpec 為任務類型的平衡參數；啟動任務與 Explain 共用，確保說明與實際計算一致。
+type taskSpec struct {
+	ID   string
+	Type task.Type
+	// Base 為基礎時長；研究點數達 ResearchCap 時縮短 MaxReduction（線性）。
+	Base         time.Duration
+	MaxReduction float64
+	ResearchCap  float64
+	// 獎勵 = RewardBase + RewardPerLevel*Level
+	RewardBase     int64
+	RewardPerLevel int64
+}
+
+var taskSpecs = map[task.Type]taskSpec{
+	// 練習：基礎 5s，最高 30% 縮短（研究達 1000）
+	task.Practice: {ID: "practice-5s", Type: task.Practice, Base: 5 * time.Second, MaxReduction: 0.3, ResearchCap: 1000, RewardBase: 10, RewardPerLevel: 2},
+	// 目標：基礎 4s，最高 40% 縮短（研究達 1200），獎勵略高於 Practice
+	task.Targeted: {ID: "targeted-4s", Type: task.Targeted, Base: 4 * time.Second, MaxReduction: 0.4, ResearchCap: 1200, RewardBase: 12, RewardPerLevel: 3},
+	// 部署：基礎 5s，最高 35% 縮短（研究達 1100）
+	task.Deploy: {ID: "deploy-5s", Type: task.Deploy, Base: 5 * time.Second, MaxReduction: 0.35, ResearchCap: 1100, RewardBase: 14, RewardPerLevel: 3},
+	// 研究：基礎 6s，最高 45% 縮短（研究達 1400）
+	task.Research: {ID: "research-6s", Type: task.Research, Base: 6 * time.Second, MaxReduction: 0.45, ResearchCap: 1400, RewardBase: 9, RewardPerLevel: 2},
+}
+
+// TaskTypes 為可啟動的任務類型（Explain 的輸出順序）。
+var TaskTypes = []task.Type{task.Practice, task.Targeted, task.Deploy, task.Research}
+
+func (s taskSpec) reduction(research int64) float64 {
+	return s.MaxReduction * math.Min(1.0, float64(research)/s.ResearchCap)
+}
+
+func (s taskSpec) duration(research int64) time.Duration {
+	return time.Duration(float64(s.Base) * (1.0 - s.reduction(research)))
+}
+
+func (s taskSpec) reward(level int) int64 { return s.RewardBase + int64(level)*s.RewardPerLevel }
+
+// 成功率參數：基礎 60%，Knowledge 以指數遞減方式最多 +35%（尺度 400），最終夾在 5%~98%。
+const (
+	successBase   = 0.60
+	successMaxInc = 0.35
+	successScale  = 400.0
+	successMin    = 0.05
+	successMax    = 0.98
+)
+
+// languageModifier 為語言難度係數（微調）。
+func languageModifier(lang string) float64 {
+	switch lang {
+	case "py", "python":
+		return 0.02
+	case "js", "javascript":
+		return -0.03
+	}
+	return 0
+}
+
+// Factor 為計算結果的一個組成項（Value 為此項的貢獻量）。
+type Factor struct {
+	Name   string
+	Value  float64
+	Detail string
+}
+
+// TaskExplanation 說明某任務類型在目前狀態下的成功率、時長與獎勵如何得出。
+type TaskExplanation struct {
+	Type     task.Type
+	Language string
+
+	Success        float64
+	SuccessFactors []Factor
+
+	BaseDuration    time.Duration
+	Duration        time.Duration
+	DurationFactors []Factor
+
+	// Reward 為成功時的 Knowledge 獎勵；Research 獎勵為 [ResearchMin, ResearchMax] 均勻分布
+	Reward        int64
+	ResearchMin   int64
+	ResearchMax   int64
+	RewardFactors []Factor
+	// 期望值（已乘上成功率）
+	ExpectedReward   float64
+	ExpectedResearch float64
+}
+
+// successFactors 回傳指定語言的成功率與各組成項。
+func (p *Player) successFactors(lang string) (float64, []Factor) {
+	factors := []Factor{{Name: "base", Value: successBase, Detail: "base success rate"}}
+	prob := successBase
+	if lang != "" {
+		// 指數遞減（diminishing returns）
+		s := p.ensureSkill(lang)
+		inc := successMaxInc * (1.0 - math.Exp(-float64(s.Knowledge)/successScale))
+		prob += inc
+		factors = append(factors, Factor{Name: "knowledge", Value: inc,
+			Detail: fmt.Sprintf("%d knowledge, diminishing returns up to +%.0f%%", s.Knowledge, successMaxInc*100)})
+	}
+	if mod := languageModifier(lang); mod != 0 {
+		prob += mod
+		factors = append(factors, Factor{Name: "language", Value: mod, Detail: lang + " difficulty modifier"})
+	}
+	if clamped := math.Max(successMin, math.Min(successMax, prob)); clamped != prob {
+		factors = append(factors, Factor{Name: "clamp", Value: clamped - prob,
+			Detail: fmt.Sprintf("limited to %.0f%%..%.0f%%", successMin*100, successMax*100)})
+		prob = clamped
+	}
+	return prob, factors
+}
+
+// Explain 說明指定任務類型若於目前語言啟動時的成功率、時長與獎勵。
+func (p *Player) Explain(tt task.Type) (TaskExplanation, error) {
+	spec, ok := taskSpecs[tt]
+	if !ok {
+		return TaskExplanation{}, ErrUnknownTaskType
+	}
+	lang := p.CurrentLanguage
+	if lang == "" {
+		lang = "go"
+	}
+	s := p.ensureSkill(lang)
+	e := TaskExplanation{Type: tt, Language: lang, BaseDuration: spec.Base}
+	e.Success, e.SuccessFactors = p.successFactors(lang)
+
+	red := spec.reduction(s.Research)
+	e.Duration = spec.duration(s.Research)
+	e.DurationFactors = []Factor{
+		{Name: "base", Value: spec.Base.Seconds(), Detail: "base duration (seconds)"},
+		{Name: "research", Value: -red, Detail: fmt.Sprintf("%d research, up to -%.0f%% at %.0f", s.Research, spec.MaxReduction*100, spec.ResearchCap)},
+	}
+
+	e.Reward = spec.reward(s.Level)
+	e.ResearchMin, e.ResearchMax = int64(p.GPUs), int64(p.GPUs+3)
+	e.RewardFactors = []Factor{
+		{Name: "base", Value: float64(spec.RewardBase), Detail: "base knowledge reward"},
+		{Name: "level", Value: float64(int64(s.Level) * spec.RewardPerLevel), Detail: fmt.Sprintf("Lv %d x %d", s.Level, spec.RewardPerLevel)},
+		{Name: "gpus", Value: float64(p.GPUs), Detail: fmt.Sprintf("research roll 0..3 shifted by %d GPUs", p.GPUs)},
+	}
+	e.ExpectedReward = e.Success * float64(e.Reward)
+	e.ExpectedResearch = e.Success * float64(e.ResearchMin+e.ResearchMax) / 2
+	return e, nil
+}
@@ -1,7 +1,6 @@
 package player
 
 import (
-	"math"
 	"math/rand"
 	"time"
 
@@ -29,8 +28,29 @@ type Player struct {
 	// Servers 提供可安裝顯卡的插槽，GPUs 佔用插槽並提升研究基礎產率。
 	Servers int
 	GPUs    int
+
+	// ProductionCarry 為被動產出尚未滿一分鐘的累積時間（Advance 使用，跨呼叫保留）。
+	ProductionCarry time.Duration
+
+	// --- 自動化（每個存檔各自設定）---
+	Automation Automation
+	// Queue 為目前任務結束後依序啟動的任務類型。
+	Queue []task.Type
 }
 
+// Automation 為伺服器端自動化設定。
+type Automation struct {
+	// Enabled 啟用時，伺服器於任務完成時間主動結算（不需客戶端呼叫 try-finish）。
+	Enabled bool
+	// AutoPractice 佇列為空時，自動開始下一個 Practice。
+	AutoPractice bool
+	// AutoPlay 啟用時，伺服器於每次自動推進後依顧問建議（advisor.GreedyROI）自動升級/購買/切換語言。
+	AutoPlay bool
+}
+
+// MaxQueue 為任務佇列長度上限。
+const MaxQueue = 5
+
 // Skill 描述單一語言的熟練度與研究點與等級。
 type Skill struct {
 	Knowledge int64
@@ -38,6 +58,23 @@ type Skill struct {
 	Level     int
 }
 
+// Clone 回傳玩家的深拷貝（Skills map 與 Current 任務皆為獨立副本），
+// 供用例在副本上操作，持久化成功後再提交。
+func (p Player) Clone() Player {
+	c := p
+	if p.Skills != nil {
+		c.Skills = make(map[string]Skill, len(p.Skills))
+		for k, v := range p.Skills {
+			c.Skills[k] = v
+		}
+	}
+	c.Current = p.Current.Clone()
+	if p.Queue != nil {
+		c.Queue = append([]task.Type(nil), p.Queue...)
+	}
+	return c
+}
+
 // ApplyOfflineGains 應用離線收益的意圖方法。
 func (p *Player) ApplyOfflineGains(knowledge, research int64, now time.Time) {
 	// 改為各語言各自累計：只加到當前語言（不再同步到全域錢包）。
@@ -51,9 +88,27 @@ func (p *Player) ApplyOfflineGains(knowledge, research int64, now time.Time) {
 }
 
 // StartPractice 啟動一個固定設定的練習任務（MVP）。
-func (p *Player) StartPractice(now time.Time) {
+func (p *Player) StartPractice(now time.Time) error {
+	return p.startSpec(taskSpecs[task.Practice], now)
+}
+
+// StartTargeted 啟動一個針對當前語言的目標任務：略短時長、略高獎勵。
+func (p *Player) StartTargeted(now time.Time) error {
+	return p.startSpec(taskSpecs[task.Targeted], now)
+}
+
+// StartDeploy 啟動部署任務：中等時長、較高知識獎勵。
+func (p *Player) StartDeploy(now time.Time) error { return p.startSpec(taskSpecs[task.Deploy], now) }
+
+// StartResearch 啟動研究任務：時長略長、知識獎勵較溫和（研究獎勵沿用既有邏輯）。
+func (p *Player) StartResearch(now time.Time) error {
+	return p.startSpec(taskSpecs[task.Research], now)
+}
+
+// startSpec 依任務規格於當前語言啟動任務：以研究點數縮短時長、以等級提高獎勵。
+func (p *Player) startSpec(spec taskSpec, now time.Time) error {
 	if p.Current != nil && p.Current.IsActive() {
-		return
+		return ErrTaskAlreadyActive
 	}
 	lang := p.CurrentLanguage
 	if lang == "" {
@@ -61,79 +116,64 @@ func (p *Player) StartPractice(now time.Time) {
 		p.CurrentLanguage = lang
 	}
 	s := p.ensureSkill(lang)
-	// 以研究點數縮短任務時間：最高 30% 縮短（研究達 1000 時達到上限）
-	base := 5 * time.Second
-	reduction := 0.3 * math.Min(1.0, float64(s.Research)/1000.0)
-	dur := time.Duration(float64(base) * (1.0 - reduction))
-	// 以等級略增獎勵
-	reward := int64(10 + s.Level*2)
-	t := &task.Task{ID: "practice-5s", Type: task.Practice, Language: lang, Duration: dur, BaseReward: reward}
+	t := &task.Task{ID: spec.ID, Type: spec.Type, Language: lang, Duration: spec.duration(s.Research), BaseReward: spec.reward(s.Level)}
 	t.Start(now)
 	p.Current = t
+	return nil
 }
 
-// StartTargeted 啟動一個針對當前語言的目標任務：略短時長、略高獎勵。
-func (p *Player) StartTargeted(now time.Time) {
-	if p.Current != nil && p.Current.IsActive() {
-		return
+// StartTask 依類型啟動任務。
+func (p *Player) StartTask(tt task.Type, now time.Time) error {
+	switch tt {
+	case task.Practice:
+		return p.StartPractice(now)
+	case task.Targeted:
+		return p.StartTargeted(now)
+	case task.Deploy:
+		return p.StartDeploy(now)
+	case task.Research:
+		return p.StartResearch(now)
 	}
-	lang := p.CurrentLanguage
-	if lang == "" {
-		lang = "go"
-		p.CurrentLanguage = lang
-	}
-	s := p.ensureSkill(lang)
-	// 以研究點數縮短任務時間：上限 40% 縮短（比 Practice 略高）
-	base := 4 * time.Second
-	reduction := 0.4 * math.Min(1.0, float64(s.Research)/1200.0)
-	dur := time.Duration(float64(base) * (1.0 - reduction))
-	// 獎勵略高於 Practice
-	reward := int64(12 + s.Level*3)
-	t := &task.Task{ID: "targeted-4s", Type: task.Targeted, Language: lang, Duration: dur, BaseReward: reward}
-	t.Start(now)
-	p.Current = t
+	return ErrUnknownTaskType
 }
 
-// StartDeploy 啟動部署任務：中等時長、較高知識獎勵。
-func (p *Player) StartDeploy(now time.Time) {
-	if p.Current != nil && p.Current.IsActive() {
-		return
+// EnqueueTask 將任務加入佇列，於目前任務結算後依序啟動。
+func (p *Player) EnqueueTask(tt task.Type) error {
+	if !tt.Valid() {
+		return ErrUnknownTaskType
 	}
-	lang := p.CurrentLanguage
-	if lang == "" {
-		lang = "go"
-		p.CurrentLanguage = lang
+	if len(p.Queue) >= MaxQueue {
+		return ErrQueueFull
 	}
-	s := p.ensureSkill(lang)
-	// 部署：基礎 5s，最高 35% 縮短（研究達 1100）
-	base := 5 * time.Second
-	reduction := 0.35 * math.Min(1.0, float64(s.Research)/1100.0)
-	dur := time.Duration(float64(base) * (1.0 - reduction))
-	reward := int64(14 + s.Level*3)
-	t := &task.Task{ID: "deploy-5s", Type: task.Deploy, Language: lang, Duration: dur, BaseReward: reward}
-	t.Start(now)
-	p.Current = t
+	p.Queue = append(p.Queue, tt)
+	return nil
 }
 
-// StartResearch 啟動研究任務：時長略長、知識獎勵較溫和（研究獎勵沿用既有邏輯）。
-func (p *Player) StartResearch(now time.Time) {
+// StartNext 若目前閒置，於 at 時間點啟動佇列中的下一個任務；佇列為空且啟用自動 Practice 時改開 Practice。
+func (p *Player) StartNext(at time.Time) {
 	if p.Current != nil && p.Current.IsActive() {
 		return
 	}
-	lang := p.CurrentLanguage
-	if lang == "" {
-		lang = "go"
-		p.CurrentLanguage = lang
+	if len(p.Queue) > 0 {
+		next := p.Queue[0]
+		p.Queue = p.Queue[1:]
+		_ = p.StartTask(next, at) // 佇列只接受合法類型且此時閒置，不會失敗
+		return
 	}
-	s := p.ensureSkill(lang)
-	// 研究：基礎 6s，最高 45% 縮短（研究達 1400）
-	base := 6 * time.Second
-	reduction := 0.45 * math.Min(1.0, float64(s.Research)/1400.0)
-	dur := time.Duration(float64(base) * (1.0 - reduction))
-	reward := int64(9 + s.Level*2)
-	t := &task.Task{ID: "research-6s", Type: task.Research, Language: lang, Duration: dur, BaseReward: reward}
-	t.Start(now)
-	p.Current = t
+	if p.AutoPractice() {
+		_ = p.StartPractice(at)
+	}
+}
+
+// AutoPractice 回傳佇列為空時是否自動開始 Practice。
+func (p *Player) AutoPractice() bool { return p.Automation.Enabled && p.Automation.AutoPractice }
+
+// NextWake 回傳自動化需要喚醒的時間點（目前任務完成時間）；無需喚醒時回傳 false。
+func (p *Player) NextWake() (time.Time, bool) {
+	if !p.Automation.Enabled || p.Current == nil || !p.Current.IsActive() {
+		return time.Time{}, false
+	}
+	return p.Current.DoneAt(), true
 }
 
 // TryFinish 嘗試完成當前任務，若完成則結算獎勵。
@@ -177,29 +217,7 @@ func (p *Player) EstimatedSuccess() float64 {
 // EstimatedSuccessFor 計算指定語言的成功率（0~1）。
 // 使用與 EstimatedSuccess 相同的邏輯，但可針對任務啟動時的語言計算。
 func (p *Player) EstimatedSuccessFor(lang string) float64 {
-	base := 0.60
-	inc := 0.0
-	if lang != "" {
-		// 指數遞減（diminishing returns）
-		s := p.ensureSkill(lang)
-		K := float64(s.Knowledge)
-		maxInc := 0.35
-		scale := 400.0
-		inc = maxInc * (1.0 - math.Exp(-K/scale))
-	}
-	prob := base + inc
-	// 語言難度係數（微調）
-	switch lang {
-	case "py", "python":
-		prob += 0.02
-	case "js", "javascript":
-		prob -= 0.03
-	}
-	if prob < 0.05 {
-		prob = 0.05
-	} else if prob > 0.98 {
-		prob = 0.98
-	}
+	prob, _ := p.successFactors(lang)
 	return prob
 }
 
@@ -234,7 +252,7 @@ func (p *Player) NextUpgradeCost() int64 {
 }
 
 // UpgradeKnowledge 嘗試進行升級：扣除當前語言的研究點，Level+1（各語言獨立）。
-func (p *Player) UpgradeKnowledge() bool {
+func (p *Player) UpgradeKnowledge() error {
 	cost := p.NextUpgradeCost()
 	// 從當前語言的研究點扣款（並維持全域相容：同步扣全域錢包）。
 	lang := p.CurrentLanguage
@@ -244,21 +262,35 @@ func (p *Player) UpgradeKnowledge() bool {
 	}
 	s := p.ensureSkill(lang)
 	if s.Research < cost {
-		return false
+		return ErrInsufficientResearch
 	}
 	s.Research -= cost
 	s.Level++
 	p.Skills[lang] = s
-	return true
+	return nil
 }
 
-// SelectLanguage 設定當前語言；若尚未存在則初始化技能。
-func (p *Player) SelectLanguage(lang string) {
-	if lang == "" {
-		return
+// SelectLanguage 設定當前語言；若尚未存在則初始化技能。未開放的語言回傳 ErrLanguageLocked。
+func (p *Player) SelectLanguage(lang string) error {
+	if !LanguageUnlocked(lang) {
+		return ErrLanguageLocked
 	}
 	_ = p.ensureSkill(lang)
 	p.CurrentLanguage = lang
+	return nil
+}
+
+// UnlockedLanguages 為目前開放的語言代碼。
+var UnlockedLanguages = []string{"go", "py", "js"}
+
+// LanguageUnlocked 判斷語言是否已開放。
+func LanguageUnlocked(lang string) bool {
+	for _, l := range UnlockedLanguages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
 }
 
 func (p *Player) ensureSkill(lang string) Skill {
@@ -297,7 +329,7 @@ const (
 )
 
 // BuyServer 嘗試購買一台伺服器主機（消耗當前語言的 Knowledge）。
-func (p *Player) BuyServer() bool {
+func (p *Player) BuyServer() error {
 	lang := p.CurrentLanguage
 	if lang == "" {
 		lang = "go"
@@ -305,23 +337,20 @@ func (p *Player) BuyServer() bool {
 	}
 	s := p.ensureSkill(lang)
 	if s.Knowledge < ServerCostK {
-		return false
+		return ErrInsufficientKnowledge
 	}
 	s.Knowledge -= ServerCostK
 	p.Skills[lang] = s
 	p.Servers++
-	return true
+	return nil
 }
 
 // BuyGPU 嘗試購買一張顯卡（需有可用插槽，並消耗 Knowledge）。
-func (p *Player) BuyGPU() bool {
+func (p *Player) BuyGPU() error {
 	// 容量限制：需先有伺服器以提供插槽
 	cap := p.Servers * SlotsPerServer
-	if cap <= 0 {
-		return false
-	}
 	if p.GPUs >= cap {
-		return false
+		return ErrNoFreeSlot
 	}
 	lang := p.CurrentLanguage
 	if lang == "" {
@@ -330,10 +359,10 @@ func (p *Player) BuyGPU() bool {
 	}
 	s := p.ensureSkill(lang)
 	if s.Knowledge < GPUCostK {
-		return false
+		return ErrInsufficientKnowledge
 	}
 	s.Knowledge -= GPUCostK
 	p.Skills[lang] = s
 	p.GPUs++
-	return true
+	return nil
 }
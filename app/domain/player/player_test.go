@@ -0,0 +1,110 @@
+package player
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"go-ddd-architecture/app/domain/task"
+)
+
+func TestPlayer_Clone_IsIndependent(t *testing.T) {
+	p := Player{CurrentLanguage: "go", Skills: map[string]Skill{"go": {Knowledge: 10}}}
+	p.StartPractice(time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC))
+
+	c := p.Clone()
+	c.Skills["go"] = Skill{Knowledge: 99}
+	c.Current.Finish()
+
+	if p.Skills["go"].Knowledge != 10 {
+		t.Fatalf("skills shared with clone: %+v", p.Skills["go"])
+	}
+	if !p.Current.IsActive() {
+		t.Fatalf("task shared with clone")
+	}
+}
+
+func TestPlayer_Advance_ResolvesTaskAndCarriesProduction(t *testing.T) {
+	start := time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)
+	p := Player{CurrentLanguage: "go", LastSeen: start}
+	p.StartPractice(start)
+
+	res := p.Advance(start, 40*time.Second)
+	if len(res.Finished) != 1 || p.Current != nil {
+		t.Fatalf("expected practice resolved, got %+v", res)
+	}
+	if res.GainedKnowledge != 0 || p.ProductionCarry != 40*time.Second {
+		t.Fatalf("expected production carried, got %+v carry=%v", res, p.ProductionCarry)
+	}
+
+	res = p.Advance(p.LastSeen, 50*time.Second)
+	if res.GainedKnowledge != p.KnowledgePerMinute() || p.ProductionCarry != 30*time.Second {
+		t.Fatalf("expected one minute of production, got %+v carry=%v", res, p.ProductionCarry)
+	}
+	if !p.LastSeen.Equal(start.Add(90 * time.Second)) {
+		t.Fatalf("unexpected LastSeen %v", p.LastSeen)
+	}
+}
+
+func TestPlayer_Advance_IsDeterministic(t *testing.T) {
+	start := time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)
+	run := func(steps int) Player {
+		p := Player{CurrentLanguage: "go", LastSeen: start}
+		p.StartDeploy(start)
+		step := 10 * time.Minute / time.Duration(steps)
+		for i := 0; i < steps; i++ {
+			p.Advance(p.LastSeen, step)
+		}
+		return p
+	}
+	a, b := run(1), run(600)
+	if a.Skills["go"] != b.Skills["go"] {
+		t.Fatalf("advance depends on step size: %+v vs %+v", a.Skills["go"], b.Skills["go"])
+	}
+}
+
+func TestPlayer_Advance_ChainsQueueAndAutoPractice(t *testing.T) {
+	start := time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)
+	p := Player{CurrentLanguage: "go", LastSeen: start, Automation: Automation{Enabled: true, AutoPractice: true}}
+	p.StartPractice(start)
+	if err := p.EnqueueTask(task.Deploy); err != nil {
+		t.Fatalf("enqueue failed: %v", err)
+	}
+
+	res := p.Advance(start, 11*time.Second)
+	if len(res.Finished) != 2 || res.Finished[1].Type != task.Deploy {
+		t.Fatalf("expected practice then deploy resolved, got %+v", res.Finished)
+	}
+	if p.Current == nil || p.Current.Type != task.Practice || len(p.Queue) != 0 {
+		t.Fatalf("expected auto practice after queue drained, got %+v queue=%v", p.Current, p.Queue)
+	}
+}
+
+func TestPlayer_Explain_MatchesActualTask(t *testing.T) {
+	now := time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)
+	p := Player{CurrentLanguage: "js", GPUs: 2, Skills: map[string]Skill{"js": {Knowledge: 300, Research: 600, Level: 3}}}
+	for _, tt := range TaskTypes {
+		e, err := p.Explain(tt)
+		if err != nil {
+			t.Fatalf("%s: %v", tt, err)
+		}
+		sum := 0.0
+		for _, f := range e.SuccessFactors {
+			sum += f.Value
+		}
+		if math.Abs(sum-e.Success) > 1e-9 || e.Success != p.EstimatedSuccess() {
+			t.Fatalf("%s: success factors sum %.4f, success %.4f, estimated %.4f", tt, sum, e.Success, p.EstimatedSuccess())
+		}
+		q := p.Clone()
+		if err := q.StartTask(tt, now); err != nil {
+			t.Fatalf("%s: start: %v", tt, err)
+		}
+		if q.Current.Duration != e.Duration || q.Current.BaseReward != e.Reward {
+			t.Fatalf("%s: explain %v/%d, actual %v/%d", tt, e.Duration, e.Reward, q.Current.Duration, q.Current.BaseReward)
+		}
+	}
+	if _, err := p.Explain("Nap"); !errors.Is(err, ErrUnknownTaskType) {
+		t.Fatalf("expected ErrUnknownTaskType, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+// Package stats 描述玩家統計的時間序列取樣與降採樣（rollup）規則。
+package stats
+
+import "time"
+
+// Resolution 時間序列的取樣粒度。
+type Resolution string
+
+const (
+	Minute Resolution = "minute"
+	Hour   Resolution = "hour"
+	Day    Resolution = "day"
+)
+
+// Resolutions 依粒度由細到粗排列，寫入時每個粒度各保留一筆。
+var Resolutions = []Resolution{Minute, Hour, Day}
+
+// ParseResolution 解析字串；未知值回傳 false。
+func ParseResolution(s string) (Resolution, bool) {
+	switch Resolution(s) {
+	case Minute, Hour, Day:
+		return Resolution(s), true
+	}
+	return "", false
+}
+
+// Step 回傳此粒度的桶寬。
+func (r Resolution) Step() time.Duration {
+	switch r {
+	case Hour:
+		return time.Hour
+	case Day:
+		return 24 * time.Hour
+	default:
+		return time.Minute
+	}
+}
+
+// Retention 回傳此粒度保留多久；超過即修剪（Day 不修剪）。
+func (r Resolution) Retention() time.Duration {
+	switch r {
+	case Minute:
+		return 24 * time.Hour
+	case Hour:
+		return 30 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// DefaultWindow 查詢未指定起點時的預設時間窗。
+func (r Resolution) DefaultWindow() time.Duration {
+	switch r {
+	case Hour:
+		return 24 * time.Hour
+	case Day:
+		return 30 * 24 * time.Hour
+	default:
+		return time.Hour
+	}
+}
+
+// Bucket 回傳 t 所屬桶的起點（UTC）。
+func (r Resolution) Bucket(t time.Time) time.Time {
+	return t.UTC().Truncate(r.Step())
+}
+
+// LangSample 單一語言於取樣時的進度。
+type LangSample struct {
+	Knowledge int64
+	Research  int64
+	Level     int
+}
+
+// Sample 單一時間點的統計快照。
+// 降採樣採「桶內最後一筆」策略：資源/等級屬於累積量，取最後值即可代表該時段結束時的狀態。
+type Sample struct {
+	At              time.Time
+	Languages       map[string]LangSample
+	KnowledgePerMin int64
+	ResearchPerMin  int64
+	Servers         int
+	GPUs            int
+}
@@ -1,6 +1,9 @@
 package task
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Type string
 
@@ -24,6 +27,46 @@ type Task struct {
 	active    bool
 }
 
+// taskJSON 為持久化格式：包含未匯出的進度欄位，避免存讀檔後任務狀態遺失。
+type taskJSON struct {
+	ID         string
+	Type       Type
+	Language   string
+	Duration   time.Duration
+	BaseReward int64
+	StartedAt  time.Time
+	DoneAt     time.Time
+	Active     bool
+}
+
+func (t Task) MarshalJSON() ([]byte, error) {
+	return json.Marshal(taskJSON{
+		ID: t.ID, Type: t.Type, Language: t.Language, Duration: t.Duration, BaseReward: t.BaseReward,
+		StartedAt: t.startedAt, DoneAt: t.doneAt, Active: t.active,
+	})
+}
+
+func (t *Task) UnmarshalJSON(b []byte) error {
+	var w taskJSON
+	if err := json.Unmarshal(b, &w); err != nil {
+		return err
+	}
+	*t = Task{
+		ID: w.ID, Type: w.Type, Language: w.Language, Duration: w.Duration, BaseReward: w.BaseReward,
+		startedAt: w.StartedAt, doneAt: w.DoneAt, active: w.Active,
+	}
+	return nil
+}
+
+// Valid 判斷是否為已知的任務類型。
+func (tt Type) Valid() bool {
+	switch tt {
+	case Practice, Targeted, Deploy, Research:
+		return true
+	}
+	return false
+}
+
 func (t *Task) Start(at time.Time) {
 	if t.active {
 		return
@@ -39,6 +82,12 @@ func (t *Task) Done(at time.Time) bool { return t.active && !at.Before(t.doneAt)
 
 func (t *Task) Finish() { t.active = false }
 
+// StartedAt 回傳任務開始的時間點（未啟動時為零值）。
+func (t *Task) StartedAt() time.Time { return t.startedAt }
+
+// DoneAt 回傳任務預計完成的時間點（未啟動時為零值）。
+func (t *Task) DoneAt() time.Time { return t.doneAt }
+
 func (t *Task) RemainingSeconds(at time.Time) int64 {
 	if !t.active {
 		return 0
@@ -49,3 +98,12 @@ func (t *Task) RemainingSeconds(at time.Time) int64 {
 	}
 	return int64(d / time.Second)
 }
+
+// Clone 回傳任務的獨立副本（含未匯出的進度欄位）。
+func (t *Task) Clone() *Task {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
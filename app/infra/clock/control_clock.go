@@ -0,0 +1,102 @@
+package clock
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrInvalidScale 表示時間倍率不是正數。
+var ErrInvalidScale = errors.New("clock: scale must be positive")
+
+// ControlClock 為開發模式用的可控制時鐘：以錨點換算，
+// 遊戲時間 = 錨點遊戲時間 + (系統時間 - 錨點系統時間) × 倍率。
+// 可於執行期間平移（Advance/Set）或調整倍率（SetScale），供 QA 以分鐘測試數小時的進度與 8h 離線封頂。
+type ControlClock struct {
+	mu    sync.RWMutex
+	real  func() time.Time
+	base  time.Time // 錨點系統時間
+	at    time.Time // 錨點遊戲時間
+	scale float64
+}
+
+// NewControlClock 建立與系統時間一致、倍率 1 的時鐘。
+func NewControlClock() *ControlClock {
+	return newControlClock(time.Now)
+}
+
+func newControlClock(real func() time.Time) *ControlClock {
+	now := real()
+	return &ControlClock{real: real, base: now, at: now, scale: 1}
+}
+
+// Now 回傳目前的遊戲時間。
+func (c *ControlClock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.nowLocked(c.real())
+}
+
+func (c *ControlClock) nowLocked(real time.Time) time.Time {
+	elapsed := real.Sub(c.base)
+	return c.at.Add(time.Duration(float64(elapsed) * c.scale))
+}
+
+// reanchor 以目前時間為新錨點，使後續調整只影響之後的流逝。
+func (c *ControlClock) reanchor() {
+	real := c.real()
+	c.at = c.nowLocked(real)
+	c.base = real
+}
+
+// Advance 將遊戲時間往前（d 為負時往後）平移 d。
+func (c *ControlClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reanchor()
+	c.at = c.at.Add(d)
+}
+
+// Set 將遊戲時間設為 t，之後依目前倍率繼續流逝。
+func (c *ControlClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.base = c.real()
+	c.at = t
+}
+
+// SetScale 調整時間倍率（例如 10 表示 10 倍速），不改變目前的遊戲時間。
+func (c *ControlClock) SetScale(scale float64) error {
+	if !(scale > 0) {
+		return ErrInvalidScale
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reanchor()
+	c.scale = scale
+	return nil
+}
+
+// Scale 回傳目前的時間倍率（排程器據此換算實際等待時間）。
+func (c *ControlClock) Scale() float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.scale
+}
+
+// Reset 回到系統時間與倍率 1。
+func (c *ControlClock) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.base = c.real()
+	c.at = c.base
+	c.scale = 1
+}
+
+// Offset 回傳遊戲時間與系統時間的差距。
+func (c *ControlClock) Offset() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	real := c.real()
+	return c.nowLocked(real).Sub(real)
+}
@@ -0,0 +1,41 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestControlClock_OffsetAndScale(t *testing.T) {
+	real := time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)
+	c := newControlClock(func() time.Time { return real })
+
+	c.Advance(2 * time.Hour)
+	if got := c.Now(); !got.Equal(real.Add(2 * time.Hour)) {
+		t.Fatalf("advance: got %v", got)
+	}
+	if err := c.SetScale(10); err != nil {
+		t.Fatalf("scale: %v", err)
+	}
+	real = real.Add(time.Minute)
+	if got := c.Now(); !got.Equal(time.Date(2025, 8, 10, 12, 10, 0, 0, time.UTC)) {
+		t.Fatalf("10x: one real minute should be ten game minutes, got %v", got)
+	}
+	if off := c.Offset(); off != 2*time.Hour+9*time.Minute {
+		t.Fatalf("offset: %v", off)
+	}
+
+	epoch := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.Set(epoch)
+	real = real.Add(6 * time.Second)
+	if got := c.Now(); !got.Equal(epoch.Add(time.Minute)) {
+		t.Fatalf("set keeps scale: got %v", got)
+	}
+	if err := c.SetScale(0); err != ErrInvalidScale {
+		t.Fatalf("zero scale should be rejected, got %v", err)
+	}
+
+	c.Reset()
+	if !c.Now().Equal(real) || c.Scale() != 1 {
+		t.Fatalf("reset: %v scale=%v", c.Now(), c.Scale())
+	}
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go-ddd-architecture/app/domain/stats"
+	outPort "go-ddd-architecture/app/usecase/port/out/game"
+)
+
+// InMemoryHistory 為記憶體版統計時間序列，規則與 bbolt 版一致（各粒度最後一筆、依保留期修剪）。
+type InMemoryHistory struct {
+	mu      sync.Mutex
+	buckets map[stats.Resolution]map[int64]stats.Sample
+}
+
+func NewInMemoryHistory() *InMemoryHistory {
+	return &InMemoryHistory{buckets: map[stats.Resolution]map[int64]stats.Sample{}}
+}
+
+func (h *InMemoryHistory) Record(sample stats.Sample) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, res := range stats.Resolutions {
+		m := h.buckets[res]
+		if m == nil {
+			m = map[int64]stats.Sample{}
+			h.buckets[res] = m
+		}
+		at := res.Bucket(sample.At)
+		v := sample
+		v.At = at
+		m[at.Unix()] = v
+		if keep := res.Retention(); keep > 0 {
+			cutoff := at.Add(-keep).Unix()
+			for k := range m {
+				if k < cutoff {
+					delete(m, k)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (h *InMemoryHistory) Query(res stats.Resolution, from, to time.Time) ([]stats.Sample, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	lo, hi := res.Bucket(from).Unix(), res.Bucket(to).Unix()
+	var out []stats.Sample
+	for k, v := range h.buckets[res] {
+		if k >= lo && k <= hi {
+			out = append(out, v)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+	return out, nil
+}
+
+var _ outPort.HistoryStore = (*InMemoryHistory)(nil)
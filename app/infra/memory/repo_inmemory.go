@@ -1,8 +1,6 @@
 package memory
 
 import (
-	"time"
-
 	"go-ddd-architecture/app/domain/gametime"
 	"go-ddd-architecture/app/domain/player"
 	outPort "go-ddd-architecture/app/usecase/port/out/game"
@@ -15,7 +13,7 @@ type InMemoryRepo struct {
 }
 
 func NewInMemoryRepo() *InMemoryRepo {
-	return &InMemoryRepo{P: player.Player{}, TS: gametime.Timestamps{WallClockAtClose: time.Now()}}
+	return &InMemoryRepo{P: player.Player{}}
 }
 
 func (r *InMemoryRepo) Load() (player.Player, gametime.Timestamps, error) { return r.P, r.TS, nil }
@@ -0,0 +1,79 @@
+package bbolt
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"go-ddd-architecture/app/domain/stats"
+	outPort "go-ddd-architecture/app/usecase/port/out/game"
+)
+
+// historyBucket 回傳指定粒度的 bucket 名稱，例如 "history_minute"。
+func historyBucket(res stats.Resolution) []byte { return []byte("history_" + string(res)) }
+
+// historyKey 以桶起點的 unix 秒（big-endian）作為 key，使 cursor 依時間排序。
+func historyKey(t time.Time) []byte {
+	var k [8]byte
+	binary.BigEndian.PutUint64(k[:], uint64(t.Unix()))
+	return k[:]
+}
+
+// Record 寫入一筆取樣到各粒度的桶（同桶以最後一筆覆蓋），並修剪超出保留期的資料。
+func (s *Store) Record(sample stats.Sample) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, res := range stats.Resolutions {
+			b := tx.Bucket(historyBucket(res))
+			if b == nil {
+				return errors.New("history buckets not initialized")
+			}
+			at := res.Bucket(sample.At)
+			v := sample
+			v.At = at
+			raw, e := json.Marshal(v)
+			if e != nil {
+				return e
+			}
+			if e = b.Put(historyKey(at), raw); e != nil {
+				return e
+			}
+			if keep := res.Retention(); keep > 0 {
+				cutoff := historyKey(at.Add(-keep))
+				c := b.Cursor()
+				for k, _ := c.First(); k != nil && string(k) < string(cutoff); k, _ = c.Next() {
+					if e = c.Delete(); e != nil {
+						return e
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Query 回傳 [from, to] 內指定粒度的取樣（依時間排序）。
+func (s *Store) Query(res stats.Resolution, from, to time.Time) ([]stats.Sample, error) {
+	var out []stats.Sample
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(historyBucket(res))
+		if b == nil {
+			return errors.New("history bucket not found")
+		}
+		c := b.Cursor()
+		end := historyKey(res.Bucket(to))
+		for k, v := c.Seek(historyKey(res.Bucket(from))); k != nil && string(k) <= string(end); k, v = c.Next() {
+			var sample stats.Sample
+			if e := json.Unmarshal(v, &sample); e != nil {
+				return e
+			}
+			out = append(out, sample)
+		}
+		return nil
+	})
+	return out, err
+}
+
+var _ outPort.HistoryStore = (*Store)(nil)
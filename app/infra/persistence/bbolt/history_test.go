@@ -0,0 +1,63 @@
+package bbolt
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"go-ddd-architecture/app/domain/stats"
+)
+
+// Test rollups: samples within the same bucket collapse to the last one per resolution.
+func TestStore_History_Rollups(t *testing.T) {
+	path := tmpDB(t)
+	s, err := New(path)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close(); _ = os.Remove(path) })
+
+	base := time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 90; i++ {
+		sample := stats.Sample{At: base.Add(time.Duration(i) * time.Minute), GPUs: i}
+		if err := s.Record(sample); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+
+	mins, err := s.Query(stats.Minute, base, base.Add(2*time.Hour))
+	if err != nil || len(mins) != 90 {
+		t.Fatalf("minute series: len=%d err=%v", len(mins), err)
+	}
+	hours, err := s.Query(stats.Hour, base, base.Add(2*time.Hour))
+	if err != nil || len(hours) != 2 {
+		t.Fatalf("hour series: len=%d err=%v", len(hours), err)
+	}
+	if hours[0].GPUs != 59 || hours[1].GPUs != 89 || !hours[1].At.Equal(base.Add(time.Hour)) {
+		t.Fatalf("unexpected hour rollup: %+v", hours)
+	}
+}
+
+// Test retention: minute samples older than the retention window are pruned.
+func TestStore_History_Retention(t *testing.T) {
+	path := tmpDB(t)
+	s, err := New(path)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close(); _ = os.Remove(path) })
+
+	old := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	now := old.Add(48 * time.Hour)
+	_ = s.Record(stats.Sample{At: old})
+	_ = s.Record(stats.Sample{At: now})
+
+	mins, _ := s.Query(stats.Minute, old, now)
+	if len(mins) != 1 {
+		t.Fatalf("expected old minute sample pruned, got %d", len(mins))
+	}
+	days, _ := s.Query(stats.Day, old, now)
+	if len(days) != 2 {
+		t.Fatalf("expected day samples kept, got %d", len(days))
+	}
+}
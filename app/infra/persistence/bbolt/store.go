@@ -9,6 +9,7 @@ import (
 
 	"go-ddd-architecture/app/domain/gametime"
 	"go-ddd-architecture/app/domain/player"
+	"go-ddd-architecture/app/domain/stats"
 	outPort "go-ddd-architecture/app/usecase/port/out/game"
 )
 
@@ -39,6 +40,11 @@ func New(path string) (*Store, error) {
 		if _, e := tx.CreateBucketIfNotExists([]byte(bucketTimestamps)); e != nil {
 			return e
 		}
+		for _, res := range stats.Resolutions {
+			if _, e := tx.CreateBucketIfNotExists(historyBucket(res)); e != nil {
+				return e
+			}
+		}
 		return nil
 	}); err != nil {
 		_ = db.Close()
@@ -79,10 +85,7 @@ func (s *Store) Load() (player.Player, gametime.Timestamps, error) {
 	if err != nil {
 		return p, ts, err
 	}
-	// 首次啟動：若未存過 timestamps，避免超大 Δt 或負值，將關閉時間設為現在。
-	if ts.WallClockAtClose.IsZero() {
-		ts.WallClockAtClose = time.Now()
-	}
+	// 首次啟動時 timestamps 為零值，由用例以注入的時鐘補上關閉時間
 	return p, ts, nil
 }
 
@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package eventlog
+
+import "os"
+
+// lockFile 在不支援檔案鎖的平台上不加鎖。
+func lockFile(*os.File) error { return nil }
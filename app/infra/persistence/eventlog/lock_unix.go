@@ -0,0 +1,14 @@
+//go:build unix
+
+package eventlog
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// lockFile 對 f 取得非阻塞的獨佔鎖；關閉檔案或行程結束時自動釋放。
+func lockFile(f *os.File) error {
+	return unix.Flock(int(f.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+}
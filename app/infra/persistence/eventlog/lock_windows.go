@@ -0,0 +1,14 @@
+//go:build windows
+
+package eventlog
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockFile 對 f 取得非阻塞的獨佔鎖；關閉檔案或行程結束時自動釋放。
+func lockFile(f *os.File) error {
+	return windows.LockFileEx(windows.Handle(f.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, new(windows.Overlapped))
+}
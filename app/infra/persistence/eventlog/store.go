@@ -0,0 +1,468 @@
+// Package eventlog 提供 append-only 事件日誌型的 Repository 實作。
+package eventlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go-ddd-architecture/app/domain/gametime"
+	"go-ddd-architecture/app/domain/player"
+	outPort "go-ddd-architecture/app/usecase/port/out/game"
+)
+
+const (
+	kindSnapshot = "snapshot"
+	kindDelta    = "delta"
+
+	// DefaultSnapshotEvery 每累積多少筆 delta 追加一筆快照（checkpoint），加速 Load 回放。
+	DefaultSnapshotEvery = 100
+	// DefaultCompactEvery 日誌累積多少筆項目後自動壓縮（開啟時與寫入時檢查），限制檔案大小與啟動回放成本。
+	// 心跳每 30 秒寫入一筆，約保留 8 小時的歷史。
+	DefaultCompactEvery = 1000
+)
+
+// Entry 為日誌中的一行（JSON Lines）。
+// snapshot 帶完整狀態；delta 只帶與前一狀態相比有變動的頂層欄位。
+type Entry struct {
+	Seq     uint64                     `json:"seq"`
+	At      time.Time                  `json:"at"`
+	Kind    string                     `json:"kind"`
+	Command outPort.Command            `json:"command"`
+	Player  map[string]json.RawMessage `json:"player,omitempty"`
+	TS      *gametime.Timestamps       `json:"ts,omitempty"`
+}
+
+// ErrLocked 表示日誌已被另一個 Store（通常是執行中的伺服器）開啟。
+var ErrLocked = errors.New("eventlog: log is in use by another process")
+
+// Store 以本地 append-only 檔案保存每次狀態變更，Load 時由最近快照回放重建 Player。
+// 同一日誌同時只能由一個 Store 開啟（以旁置的 .lock 檔加鎖，行程結束即釋放）。
+type Store struct {
+	mu   sync.Mutex
+	path string
+	// f 為追加用的檔案；壓縮時會關閉後重新開啟，重開失敗時為 nil，由下次追加重試
+	f    *os.File
+	lock *os.File
+
+	snapshotEvery int
+	sinceSnapshot int
+	compactEvery  int
+	// entries 為日誌檔中的項目數
+	entries int
+	seq     uint64
+
+	// 最近一次寫入後的狀態（以欄位 JSON 表示，便於計算 delta）
+	fields map[string]json.RawMessage
+	ts     gametime.Timestamps
+
+	// truncated 為開啟時截斷的殘行位元組數
+	truncated int64
+	// onMaintenanceError 接收 delta 已寫入後的壓縮/快照失敗（不影響存檔結果）
+	onMaintenanceError func(error)
+}
+
+// New 開啟或建立日誌檔，並回放既有內容以建立目前狀態。
+// 檔尾不完整的項目（寫入中途當機）會被截斷，之後的追加才不會接在殘行後面；
+// 截斷的位元組數可由 Truncated 取得。
+func New(path string) (*Store, error) {
+	lock, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(lock); err != nil {
+		_ = lock.Close()
+		return nil, fmt.Errorf("%w: %s", ErrLocked, path)
+	}
+	s, err := open(path)
+	if err != nil {
+		_ = lock.Close()
+		return nil, err
+	}
+	s.lock = lock
+	return s, nil
+}
+
+func open(path string) (*Store, error) {
+	entries, validEnd, err := readEntries(path)
+	if err != nil {
+		return nil, err
+	}
+	var truncated int64
+	if fi, err := os.Stat(path); err == nil && fi.Size() > validEnd {
+		truncated = fi.Size() - validEnd
+		if err := os.Truncate(path, validEnd); err != nil {
+			return nil, fmt.Errorf("eventlog: truncate torn tail: %w", err)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{path: path, f: f, snapshotEvery: DefaultSnapshotEvery, compactEvery: DefaultCompactEvery, truncated: truncated}
+	s.fields, s.ts, s.sinceSnapshot = replay(entries, 0)
+	s.entries = len(entries)
+	if n := len(entries); n > 0 {
+		s.seq = entries[n-1].Seq
+	}
+	if s.compactEvery > 0 && s.entries >= s.compactEvery {
+		if err := s.compact(); err != nil {
+			_ = s.Close()
+			return nil, fmt.Errorf("eventlog: compact on open: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// SetSnapshotEvery 調整自動快照間隔；n<=0 表示不自動快照。
+func (s *Store) SetSnapshotEvery(n int) {
+	s.mu.Lock()
+	s.snapshotEvery = n
+	s.mu.Unlock()
+}
+
+// SetCompactEvery 調整自動壓縮門檻（日誌項目數）；n<=0 表示不自動壓縮。
+func (s *Store) SetCompactEvery(n int) {
+	s.mu.Lock()
+	s.compactEvery = n
+	s.mu.Unlock()
+}
+
+// Truncated 回傳開啟時因寫入中斷而截斷的位元組數（0 表示日誌完整）。
+func (s *Store) Truncated() int64 { return s.truncated }
+
+// SetMaintenanceErrorHandler 設定壓縮或快照失敗時的回報函式。
+// 這些步驟發生在 delta 已寫入之後，失敗不影響存檔，因此 SaveCommand 不回傳錯誤，只經由此處回報。
+func (s *Store) SetMaintenanceErrorHandler(fn func(error)) {
+	s.mu.Lock()
+	s.onMaintenanceError = fn
+	s.mu.Unlock()
+}
+
+// Close 釋放底層檔案與鎖。
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var err error
+	if s.f != nil {
+		err = s.f.Close()
+		s.f = nil
+	}
+	if s.lock != nil {
+		_ = s.lock.Close()
+		s.lock = nil
+	}
+	return err
+}
+
+func (s *Store) Load() (player.Player, gametime.Timestamps, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, err := decodePlayer(s.fields)
+	if err != nil {
+		return p, s.ts, err
+	}
+	// 首次啟動時 timestamps 為零值，由用例以注入的時鐘補上關閉時間
+	return p, s.ts, nil
+}
+
+func (s *Store) Save(p player.Player, ts gametime.Timestamps) error {
+	return s.SaveCommand(outPort.Command{Name: "save"}, p, ts)
+}
+
+// SaveCommand 追加一筆 delta（必要時再追加快照）。
+func (s *Store) SaveCommand(cmd outPort.Command, p player.Player, ts gametime.Timestamps) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fields, err := encodePlayer(p)
+	if err != nil {
+		return err
+	}
+	changed := map[string]json.RawMessage{}
+	for k, v := range fields {
+		if old, ok := s.fields[k]; !ok || !bytes.Equal(old, v) {
+			changed[k] = v
+		}
+	}
+	e := Entry{Seq: s.seq + 1, At: time.Now().UTC(), Kind: kindDelta, Command: cmd, Player: changed}
+	if !ts.WallClockAtClose.Equal(s.ts.WallClockAtClose) || ts.ElapsedMonotonicSeconds != s.ts.ElapsedMonotonicSeconds {
+		tsCopy := ts
+		e.TS = &tsCopy
+	}
+	if err := s.append(e); err != nil {
+		return err
+	}
+	s.fields, s.ts = fields, ts
+	s.sinceSnapshot++
+	// delta 已寫入並 fsync：之後的壓縮/快照只為加速回放，失敗僅回報，
+	// 否則用例會保留舊快取並提示存檔失敗，與已落盤的狀態不一致
+	switch {
+	case s.compactEvery > 0 && s.entries >= s.compactEvery:
+		s.maintenanceFailed(s.compact())
+	case s.snapshotEvery > 0 && s.sinceSnapshot >= s.snapshotEvery:
+		s.maintenanceFailed(s.appendSnapshot(outPort.Command{Name: "checkpoint"}))
+	}
+	return nil
+}
+
+func (s *Store) maintenanceFailed(err error) {
+	if err != nil && s.onMaintenanceError != nil {
+		s.onMaintenanceError(err)
+	}
+}
+
+// History、LoadAt 與 Rewind 供離線工具（`eventlog` 命令）使用，不可對執行中伺服器的存檔呼叫：
+// Rewind 只改變日誌，已載入狀態的用例快取不會跟著倒回。New 的檔案鎖確保伺服器執行時無法另外開啟同一日誌。
+
+// History 回傳所有日誌項目（含快照），供除錯經濟問題時檢視。
+func (s *Store) History() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, _, err := readEntries(s.path)
+	return entries, err
+}
+
+// LoadAt 回放至指定序號（含）為止的狀態，不影響目前存檔。
+func (s *Store) LoadAt(seq uint64) (player.Player, gametime.Timestamps, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, _, err := readEntries(s.path)
+	if err != nil {
+		return player.Player{}, gametime.Timestamps{}, err
+	}
+	if seq == 0 || seq > s.seq {
+		return player.Player{}, gametime.Timestamps{}, fmt.Errorf("eventlog: seq %d out of range (1..%d)", seq, s.seq)
+	}
+	fields, ts, _ := replay(entries, seq)
+	p, err := decodePlayer(fields)
+	return p, ts, err
+}
+
+// Rewind 將存檔倒回指定序號的狀態。
+// 仍維持 append-only：以追加一筆快照的方式生效，原歷史保留可再次回放。
+func (s *Store) Rewind(seq uint64) error {
+	p, ts, err := s.LoadAt(seq)
+	if err != nil {
+		return err
+	}
+	fields, err := encodePlayer(p)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fields, s.ts = fields, ts
+	return s.appendSnapshot(outPort.Command{Name: "rewind", Arg: fmt.Sprint(seq)})
+}
+
+// Compact 以目前狀態的單筆快照改寫日誌，捨棄之前的歷史以縮小檔案。
+// 先寫暫存檔再 rename，確保中途失敗不會損毀原檔。
+func (s *Store) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.compact()
+}
+
+func (s *Store) compact() error {
+	tmp := s.path + ".compact"
+	e := s.snapshotEntry(outPort.Command{Name: "compact"})
+	if err := writeSnapshotFile(tmp, e); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	// Windows 無法 rename 覆蓋開啟中的檔案，需先關閉；之後不論成功與否都重新開啟以便繼續追加
+	if s.f != nil {
+		_ = s.f.Close()
+		s.f = nil
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		_ = os.Remove(tmp)
+		return errors.Join(err, s.reopen())
+	}
+	s.seq = e.Seq
+	s.sinceSnapshot = 0
+	s.entries = 1
+	return s.reopen()
+}
+
+func writeSnapshotFile(path string, e Entry) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := writeEntry(f, e); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (s *Store) reopen() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	s.f = f
+	return nil
+}
+
+func (s *Store) snapshotEntry(cmd outPort.Command) Entry {
+	ts := s.ts
+	return Entry{Seq: s.seq + 1, At: time.Now().UTC(), Kind: kindSnapshot, Command: cmd, Player: s.fields, TS: &ts}
+}
+
+func (s *Store) appendSnapshot(cmd outPort.Command) error {
+	if err := s.append(s.snapshotEntry(cmd)); err != nil {
+		return err
+	}
+	s.sinceSnapshot = 0
+	return nil
+}
+
+// append 追加一筆項目並 fsync；寫入失敗時截回原長度，避免殘行之後再接上新項目而使日誌損毀。
+func (s *Store) append(e Entry) error {
+	if s.f == nil {
+		if err := s.reopen(); err != nil {
+			return err
+		}
+	}
+	fi, err := s.f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := writeEntry(s.f, e); err != nil {
+		_ = s.f.Truncate(fi.Size())
+		return err
+	}
+	if err := s.f.Sync(); err != nil {
+		return err
+	}
+	s.seq = e.Seq
+	s.entries++
+	return nil
+}
+
+func writeEntry(f *os.File, e Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(b, '\n'))
+	return err
+}
+
+// readEntries 讀取整份日誌；檔案不存在時回傳空集合。
+// validEnd 為最後一筆完整項目之後的位移：最後一行若不完整（寫入中途當機）則忽略，
+// 由 New 截斷；不可解析的行之後仍有資料則視為損毀並回傳錯誤，避免靜默丟棄之後的項目。
+func readEntries(path string) (entries []Entry, validEnd int64, err error) {
+	f, err := os.Open(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	var offset int64
+	for {
+		line, rerr := r.ReadBytes('\n')
+		if len(line) > 0 {
+			var e Entry
+			switch {
+			case len(bytes.TrimSpace(line)) == 0:
+			case line[len(line)-1] != '\n' || json.Unmarshal(line, &e) != nil:
+				// 不完整或不可解析：只允許出現在檔尾
+				if rest, _ := io.ReadAll(r); len(bytes.TrimSpace(rest)) > 0 {
+					return nil, 0, fmt.Errorf("eventlog: corrupt entry at offset %d in %s", offset, path)
+				}
+				return entries, offset, nil
+			default:
+				entries = append(entries, e)
+			}
+			offset += int64(len(line))
+		}
+		if errors.Is(rerr, io.EOF) {
+			return entries, offset, nil
+		}
+		if rerr != nil {
+			return nil, 0, rerr
+		}
+	}
+}
+
+// replay 從最近（序號不超過 upTo）的快照開始套用 delta；upTo=0 表示回放全部。
+// 回傳狀態欄位、timestamps 與快照後的 delta 數量。
+func replay(entries []Entry, upTo uint64) (map[string]json.RawMessage, gametime.Timestamps, int) {
+	start := 0
+	for i, e := range entries {
+		if upTo > 0 && e.Seq > upTo {
+			break
+		}
+		if e.Kind == kindSnapshot {
+			start = i
+		}
+	}
+	fields := map[string]json.RawMessage{}
+	var ts gametime.Timestamps
+	deltas := 0
+	for _, e := range entries[start:] {
+		if upTo > 0 && e.Seq > upTo {
+			break
+		}
+		if e.Kind == kindSnapshot {
+			fields = map[string]json.RawMessage{}
+			deltas = 0
+		} else {
+			deltas++
+		}
+		for k, v := range e.Player {
+			fields[k] = v
+		}
+		if e.TS != nil {
+			ts = *e.TS
+		}
+	}
+	return fields, ts, deltas
+}
+
+func encodePlayer(p player.Player) (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func decodePlayer(fields map[string]json.RawMessage) (player.Player, error) {
+	var p player.Player
+	if len(fields) == 0 {
+		return p, nil
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return p, err
+	}
+	err = json.Unmarshal(b, &p)
+	return p, err
+}
+
+var (
+	_ outPort.Repository    = (*Store)(nil)
+	_ outPort.CommandLogger = (*Store)(nil)
+)
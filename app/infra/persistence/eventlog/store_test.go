@@ -0,0 +1,241 @@
+package eventlog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-ddd-architecture/app/domain/gametime"
+	"go-ddd-architecture/app/domain/player"
+	outPort "go-ddd-architecture/app/usecase/port/out/game"
+)
+
+func tmpLog(t *testing.T) string {
+	t.Helper()
+	return filepath.Join(t.TempDir(), "game.log")
+}
+
+// Test replay: reopening the log rebuilds the last saved state.
+func TestStore_ReplayOnReopen(t *testing.T) {
+	path := tmpLog(t)
+	s, err := New(path)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	ts := gametime.Timestamps{WallClockAtClose: time.Unix(1700000000, 0).UTC()}
+	p := player.Player{ID: "p1", CurrentLanguage: "go", Skills: map[string]player.Skill{"go": {Knowledge: 10}}}
+	if err := s.SaveCommand(outPort.Command{Name: "claim-offline"}, p, ts); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	p.Servers = 1
+	p.Skills = map[string]player.Skill{"go": {Knowledge: 5}}
+	if err := s.SaveCommand(outPort.Command{Name: "buy-server"}, p, ts); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	_ = s.Close()
+
+	s2, err := New(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	t.Cleanup(func() { _ = s2.Close() })
+	p2, ts2, err := s2.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if p2.ID != "p1" || p2.Servers != 1 || p2.Skills["go"].Knowledge != 5 {
+		t.Fatalf("unexpected replayed player: %+v", p2)
+	}
+	if !ts2.WallClockAtClose.Equal(ts.WallClockAtClose) {
+		t.Fatalf("timestamps mismatch: %v != %v", ts2.WallClockAtClose, ts.WallClockAtClose)
+	}
+	hist, err := s2.History()
+	if err != nil || len(hist) != 2 || hist[1].Command.Name != "buy-server" {
+		t.Fatalf("unexpected history: %+v err=%v", hist, err)
+	}
+	if _, ok := hist[1].Player["ID"]; ok {
+		t.Fatalf("delta should only carry changed fields: %v", hist[1].Player)
+	}
+}
+
+// Test rewind and compaction keep the log consistent with automatic checkpoints.
+func TestStore_RewindAndCompact(t *testing.T) {
+	path := tmpLog(t)
+	s, err := New(path)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	s.SetSnapshotEvery(3)
+
+	ts := gametime.Timestamps{WallClockAtClose: time.Unix(1700000000, 0).UTC()}
+	for i := 1; i <= 7; i++ {
+		if err := s.Save(player.Player{GPUs: i}, ts); err != nil {
+			t.Fatalf("save %d: %v", i, err)
+		}
+	}
+	p, _, err := s.LoadAt(2)
+	if err != nil || p.GPUs != 2 {
+		t.Fatalf("load at 2: gpus=%d err=%v", p.GPUs, err)
+	}
+	if err := s.Rewind(2); err != nil {
+		t.Fatalf("rewind: %v", err)
+	}
+	if p, _, _ := s.Load(); p.GPUs != 2 {
+		t.Fatalf("expected rewound gpus=2, got %d", p.GPUs)
+	}
+	if err := s.Compact(); err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	hist, _ := s.History()
+	if len(hist) != 1 || hist[0].Kind != kindSnapshot {
+		t.Fatalf("expected single snapshot after compact, got %+v", hist)
+	}
+	if p, _, _ := s.Load(); p.GPUs != 2 {
+		t.Fatalf("compact changed state: gpus=%d", p.GPUs)
+	}
+}
+
+// Test a torn final line (crash mid-write) is truncated so later appends replay normally,
+// while corruption before valid entries fails loudly.
+func TestStore_TornTailTruncated(t *testing.T) {
+	path := tmpLog(t)
+	s, err := New(path)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	ts := gametime.Timestamps{WallClockAtClose: time.Unix(1700000000, 0).UTC()}
+	if err := s.Save(player.Player{GPUs: 1}, ts); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	_ = s.Close()
+	appendRaw(t, path, `{"seq":2,"kind":"del`)
+
+	s, err = New(path)
+	if err != nil {
+		t.Fatalf("reopen torn: %v", err)
+	}
+	if s.Truncated() == 0 {
+		t.Fatalf("expected torn tail to be truncated")
+	}
+	for i := 2; i <= 3; i++ {
+		if err := s.Save(player.Player{GPUs: i}, ts); err != nil {
+			t.Fatalf("save %d: %v", i, err)
+		}
+	}
+	_ = s.Close()
+
+	s, err = New(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if p, _, _ := s.Load(); p.GPUs != 3 || s.Truncated() != 0 {
+		t.Fatalf("entries after torn line lost: gpus=%d truncated=%d", p.GPUs, s.Truncated())
+	}
+	_ = s.Close()
+
+	appendRaw(t, path, "garbage\n"+`{"seq":9,"kind":"snapshot"}`+"\n")
+	if _, err := New(path); err == nil {
+		t.Fatalf("expected error for corruption before valid entries")
+	}
+}
+
+func appendRaw(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test the log is compacted automatically once it reaches the configured size.
+func TestStore_AutoCompact(t *testing.T) {
+	path := tmpLog(t)
+	s, err := New(path)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	s.SetCompactEvery(5)
+	ts := gametime.Timestamps{WallClockAtClose: time.Unix(1700000000, 0).UTC()}
+	for i := 1; i <= 12; i++ {
+		if err := s.Save(player.Player{GPUs: i}, ts); err != nil {
+			t.Fatalf("save %d: %v", i, err)
+		}
+	}
+	hist, _ := s.History()
+	if len(hist) >= 5 || hist[0].Kind != kindSnapshot {
+		t.Fatalf("log not compacted: %d entries", len(hist))
+	}
+	_ = s.Close()
+
+	s, err = New(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	if p, _, _ := s.Load(); p.GPUs != 12 {
+		t.Fatalf("compaction lost state: gpus=%d", p.GPUs)
+	}
+}
+
+// Test a failed compaction after the delta is durable does not fail the save, and later saves still append.
+func TestStore_CompactFailureIsBestEffort(t *testing.T) {
+	path := tmpLog(t)
+	s, err := New(path)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	var failures int
+	s.SetMaintenanceErrorHandler(func(error) { failures++ })
+	s.SetCompactEvery(2)
+	// 以非空的同名目錄佔住暫存檔路徑，使壓縮失敗
+	if err := os.MkdirAll(filepath.Join(path+".compact", "x"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	ts := gametime.Timestamps{WallClockAtClose: time.Unix(1700000000, 0).UTC()}
+	for i := 1; i <= 3; i++ {
+		if err := s.Save(player.Player{GPUs: i}, ts); err != nil {
+			t.Fatalf("save %d should succeed despite compaction failure: %v", i, err)
+		}
+	}
+	if failures == 0 {
+		t.Fatalf("compaction failure should be reported")
+	}
+	if err := os.RemoveAll(path + ".compact"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(player.Player{GPUs: 4}, ts); err != nil {
+		t.Fatalf("save after recovery: %v", err)
+	}
+	if hist, _ := s.History(); len(hist) != 1 || hist[0].Kind != kindSnapshot {
+		t.Fatalf("expected compaction to succeed once possible: %+v", hist)
+	}
+	if p, _, _ := s.Load(); p.GPUs != 4 {
+		t.Fatalf("unexpected state: gpus=%d", p.GPUs)
+	}
+}
+
+// Test a log opened by one Store (e.g. the running server) cannot be opened again until closed.
+func TestStore_LockedWhileOpen(t *testing.T) {
+	path := tmpLog(t)
+	s, err := New(path)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if _, err := New(path); !errors.Is(err, ErrLocked) {
+		t.Fatalf("second open should fail with ErrLocked: %v", err)
+	}
+	_ = s.Close()
+	s, err = New(path)
+	if err != nil {
+		t.Fatalf("reopen after close: %v", err)
+	}
+	_ = s.Close()
+}
@@ -1,2 +1,80 @@
-// Package mongo
+// Package mongo 提供以文件庫（MongoDB 相容）保存遊戲存檔的 Repository。
 package mongo
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	mgo "go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Connect 連線至 MongoDB 相容的伺服器並回傳指定資料庫的 Driver。
+// uri 為 "memory://" 時回傳行程內的 MemoryDriver（開發/測試用，不需要資料庫）。
+func Connect(ctx context.Context, uri, database string) (Driver, error) {
+	if uri == "memory://" {
+		return NewMemoryDriver(), nil
+	}
+	client, err := mgo.Connect(options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, err
+	}
+	return &serverDriver{client: client, db: client.Database(database)}, nil
+}
+
+// serverDriver 以官方 driver 實作 Driver。
+type serverDriver struct {
+	client *mgo.Client
+	db     *mgo.Database
+}
+
+func (d *serverDriver) Collection(name string) Collection {
+	return &serverCollection{c: d.db.Collection(name)}
+}
+
+func (d *serverDriver) Close(ctx context.Context) error { return d.client.Disconnect(ctx) }
+
+type serverCollection struct {
+	c *mgo.Collection
+}
+
+func (c *serverCollection) FindOne(ctx context.Context, id string, out any) error {
+	err := c.c.FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(out)
+	if errors.Is(err, mgo.ErrNoDocuments) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (c *serverCollection) Upsert(ctx context.Context, id string, doc any) error {
+	_, err := c.c.ReplaceOne(ctx, bson.D{{Key: "_id", Value: id}}, doc, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (c *serverCollection) IDs(ctx context.Context) ([]string, error) {
+	cur, err := c.c.Find(ctx, bson.D{}, options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var ids []string
+	for cur.Next(ctx) {
+		var row struct {
+			ID string `bson:"_id"`
+		}
+		if err := cur.Decode(&row); err != nil {
+			return nil, err
+		}
+		ids = append(ids, row.ID)
+	}
+	sort.Strings(ids)
+	return ids, cur.Err()
+}
+
+var _ Driver = (*serverDriver)(nil)
@@ -0,0 +1,26 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound 由 Collection.FindOne 在文件不存在時回傳。
+var ErrNotFound = errors.New("mongo: document not found")
+
+// Collection 為 Repository 需要的最小文件操作集合（以 _id 存取）。
+// 真實 MongoDB 與測試用的記憶體 fake 都實作此介面。
+type Collection interface {
+	// FindOne 以 id 取回文件並解碼到 out；不存在時回傳 ErrNotFound。
+	FindOne(ctx context.Context, id string, out any) error
+	// Upsert 以 id 整份取代文件（不存在則新增）。
+	Upsert(ctx context.Context, id string, doc any) error
+	// IDs 列出集合內所有文件 id。
+	IDs(ctx context.Context) ([]string, error)
+}
+
+// Driver 提供集合存取與資源釋放。
+type Driver interface {
+	Collection(name string) Collection
+	Close(ctx context.Context) error
+}
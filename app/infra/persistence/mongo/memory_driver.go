@@ -0,0 +1,68 @@
+package mongo
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// MemoryDriver 為行程內的 Driver fake：以 BSON 編碼保存文件（與真實 driver 相同的編碼規則），
+// 寫入後修改原物件不影響已存資料，可在無資料庫的環境下測試 Repository。
+type MemoryDriver struct {
+	mu   sync.Mutex
+	cols map[string]map[string][]byte
+}
+
+func NewMemoryDriver() *MemoryDriver {
+	return &MemoryDriver{cols: map[string]map[string][]byte{}}
+}
+
+func (d *MemoryDriver) Collection(name string) Collection { return &memoryCollection{d: d, name: name} }
+
+func (d *MemoryDriver) Close(context.Context) error { return nil }
+
+type memoryCollection struct {
+	d    *MemoryDriver
+	name string
+}
+
+func (c *memoryCollection) FindOne(_ context.Context, id string, out any) error {
+	c.d.mu.Lock()
+	raw, ok := c.d.cols[c.name][id]
+	c.d.mu.Unlock()
+	if !ok {
+		return ErrNotFound
+	}
+	return bson.Unmarshal(raw, out)
+}
+
+func (c *memoryCollection) Upsert(_ context.Context, id string, doc any) error {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	col := c.d.cols[c.name]
+	if col == nil {
+		col = map[string][]byte{}
+		c.d.cols[c.name] = col
+	}
+	col[id] = raw
+	return nil
+}
+
+func (c *memoryCollection) IDs(context.Context) ([]string, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	ids := make([]string, 0, len(c.d.cols[c.name]))
+	for id := range c.d.cols[c.name] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+var _ Driver = (*MemoryDriver)(nil)
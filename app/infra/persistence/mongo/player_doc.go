@@ -0,0 +1,153 @@
+package mongo
+
+import (
+	"time"
+
+	"go-ddd-architecture/app/domain/gametime"
+	"go-ddd-architecture/app/domain/player"
+	"go-ddd-architecture/app/domain/resource"
+	"go-ddd-architecture/app/domain/task"
+)
+
+// playerDoc 為 player.Player 的 BSON 文件格式：欄位名稱固定（camelCase），可直接在集合上查詢，
+// 與領域結構解耦；時長以奈秒整數保存，時間為 BSON 日期（毫秒精度）。
+type playerDoc struct {
+	ID                   string              `bson:"id"`
+	Wallet               walletDoc           `bson:"wallet"`
+	LastSeen             time.Time           `bson:"lastSeen"`
+	Prestige             int                 `bson:"prestige"`
+	Level                int                 `bson:"level"`
+	Current              *taskDoc            `bson:"current,omitempty"`
+	CurrentLanguage      string              `bson:"currentLanguage"`
+	Skills               map[string]skillDoc `bson:"skills,omitempty"`
+	Servers              int                 `bson:"servers"`
+	GPUs                 int                 `bson:"gpus"`
+	ProductionCarryNanos int64               `bson:"productionCarryNanos"`
+	Automation           automationDoc       `bson:"automation"`
+	Queue                []string            `bson:"queue,omitempty"`
+}
+
+type walletDoc struct {
+	Knowledge int64 `bson:"knowledge"`
+	Research  int64 `bson:"research"`
+}
+
+type skillDoc struct {
+	Knowledge int64 `bson:"knowledge"`
+	Research  int64 `bson:"research"`
+	Level     int   `bson:"level"`
+}
+
+type automationDoc struct {
+	Enabled      bool `bson:"enabled"`
+	AutoPractice bool `bson:"autoPractice"`
+	AutoPlay     bool `bson:"autoPlay"`
+}
+
+// taskDoc 保存任務定義與進度（開始/完成時間、是否進行中），讀回後任務可接續計時。
+type taskDoc struct {
+	ID            string    `bson:"id"`
+	Type          string    `bson:"type"`
+	Language      string    `bson:"language"`
+	DurationNanos int64     `bson:"durationNanos"`
+	BaseReward    int64     `bson:"baseReward"`
+	StartedAt     time.Time `bson:"startedAt,omitempty"`
+	DoneAt        time.Time `bson:"doneAt,omitempty"`
+	Active        bool      `bson:"active"`
+}
+
+type timestampsDoc struct {
+	WallClockAtClose        time.Time `bson:"wallClockAtClose"`
+	ElapsedMonotonicSeconds int64     `bson:"elapsedMonotonicSeconds"`
+}
+
+func newPlayerDoc(p player.Player) playerDoc {
+	d := playerDoc{
+		ID:                   p.ID,
+		Wallet:               walletDoc{Knowledge: p.Wallet.Knowledge, Research: p.Wallet.Research},
+		LastSeen:             p.LastSeen,
+		Prestige:             p.Prestige,
+		Level:                p.Level,
+		Current:              newTaskDoc(p.Current),
+		CurrentLanguage:      p.CurrentLanguage,
+		Servers:              p.Servers,
+		GPUs:                 p.GPUs,
+		ProductionCarryNanos: int64(p.ProductionCarry),
+		Automation:           automationDoc{Enabled: p.Automation.Enabled, AutoPractice: p.Automation.AutoPractice, AutoPlay: p.Automation.AutoPlay},
+	}
+	if p.Skills != nil {
+		d.Skills = make(map[string]skillDoc, len(p.Skills))
+		for lang, s := range p.Skills {
+			d.Skills[lang] = skillDoc{Knowledge: s.Knowledge, Research: s.Research, Level: s.Level}
+		}
+	}
+	for _, tt := range p.Queue {
+		d.Queue = append(d.Queue, string(tt))
+	}
+	return d
+}
+
+func (d playerDoc) toPlayer() player.Player {
+	p := player.Player{
+		ID:              d.ID,
+		Wallet:          resource.Wallet{Knowledge: d.Wallet.Knowledge, Research: d.Wallet.Research},
+		LastSeen:        d.LastSeen,
+		Prestige:        d.Prestige,
+		Level:           d.Level,
+		Current:         d.Current.toTask(),
+		CurrentLanguage: d.CurrentLanguage,
+		Servers:         d.Servers,
+		GPUs:            d.GPUs,
+		ProductionCarry: time.Duration(d.ProductionCarryNanos),
+		Automation:      player.Automation{Enabled: d.Automation.Enabled, AutoPractice: d.Automation.AutoPractice, AutoPlay: d.Automation.AutoPlay},
+	}
+	if d.Skills != nil {
+		p.Skills = make(map[string]player.Skill, len(d.Skills))
+		for lang, s := range d.Skills {
+			p.Skills[lang] = player.Skill{Knowledge: s.Knowledge, Research: s.Research, Level: s.Level}
+		}
+	}
+	for _, tt := range d.Queue {
+		p.Queue = append(p.Queue, task.Type(tt))
+	}
+	return p
+}
+
+func newTaskDoc(t *task.Task) *taskDoc {
+	if t == nil {
+		return nil
+	}
+	return &taskDoc{
+		ID:            t.ID,
+		Type:          string(t.Type),
+		Language:      t.Language,
+		DurationNanos: int64(t.Duration),
+		BaseReward:    t.BaseReward,
+		StartedAt:     t.StartedAt(),
+		DoneAt:        t.DoneAt(),
+		Active:        t.IsActive(),
+	}
+}
+
+// toTask 以領域方法重建進度：Start 依開始時間與時長推得完成時間，未進行中者再 Finish。
+func (d *taskDoc) toTask() *task.Task {
+	if d == nil {
+		return nil
+	}
+	t := &task.Task{ID: d.ID, Type: task.Type(d.Type), Language: d.Language, Duration: time.Duration(d.DurationNanos), BaseReward: d.BaseReward}
+	if !d.StartedAt.IsZero() {
+		t.Start(d.StartedAt)
+		if !d.Active {
+			t.Finish()
+		}
+	}
+	return t
+}
+
+func newTimestampsDoc(ts gametime.Timestamps) timestampsDoc {
+	return timestampsDoc{WallClockAtClose: ts.WallClockAtClose, ElapsedMonotonicSeconds: ts.ElapsedMonotonicSeconds}
+}
+
+func (d timestampsDoc) toTimestamps() gametime.Timestamps {
+	return gametime.Timestamps{WallClockAtClose: d.WallClockAtClose, ElapsedMonotonicSeconds: d.ElapsedMonotonicSeconds}
+}
@@ -0,0 +1,81 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-ddd-architecture/app/domain/gametime"
+	"go-ddd-architecture/app/domain/player"
+	outPort "go-ddd-architecture/app/usecase/port/out/game"
+)
+
+const (
+	collectionSaves = "saves"
+	// DefaultProfile 未指定 profile 時使用的存檔名稱。
+	DefaultProfile = "default"
+	opTimeout      = 5 * time.Second
+)
+
+// saveDoc 為單一 profile 的存檔文件（_id = profile 名稱）。
+type saveDoc struct {
+	ID         string        `bson:"_id"`
+	Player     playerDoc     `bson:"player"`
+	Timestamps timestampsDoc `bson:"timestamps"`
+	UpdatedAt  time.Time     `bson:"updatedAt"`
+}
+
+// Repository 實作 Game 用例的 Repository，每個 profile 對應 saves 集合中的一份文件。
+type Repository struct {
+	drv     Driver
+	saves   Collection
+	profile string
+}
+
+// New 建立指定 profile 的 Repository；profile 為空時使用 DefaultProfile。
+func New(drv Driver, profile string) *Repository {
+	if profile == "" {
+		profile = DefaultProfile
+	}
+	return &Repository{drv: drv, saves: drv.Collection(collectionSaves), profile: profile}
+}
+
+// Profile 回傳目前操作的 profile 名稱。
+func (r *Repository) Profile() string { return r.profile }
+
+// ForProfile 回傳共用同一 Driver、但操作另一份存檔的 Repository。
+func (r *Repository) ForProfile(profile string) *Repository { return New(r.drv, profile) }
+
+// Profiles 列出已存在的所有 profile。
+func (r *Repository) Profiles() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+	return r.saves.IDs(ctx)
+}
+
+func (r *Repository) Load() (player.Player, gametime.Timestamps, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+	var doc saveDoc
+	err := r.saves.FindOne(ctx, r.profile, &doc)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return player.Player{}, gametime.Timestamps{}, err
+	}
+	// 首次啟動時 timestamps 為零值，由用例以注入的時鐘補上關閉時間
+	return doc.Player.toPlayer(), doc.Timestamps.toTimestamps(), nil
+}
+
+func (r *Repository) Save(p player.Player, ts gametime.Timestamps) error {
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+	return r.saves.Upsert(ctx, r.profile, saveDoc{ID: r.profile, Player: newPlayerDoc(p), Timestamps: newTimestampsDoc(ts), UpdatedAt: time.Now().UTC()})
+}
+
+// Close 釋放底層 Driver。
+func (r *Repository) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+	return r.drv.Close(ctx)
+}
+
+var _ outPort.Repository = (*Repository)(nil)
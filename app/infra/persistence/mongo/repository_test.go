@@ -0,0 +1,136 @@
+package mongo
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-ddd-architecture/app/domain/gametime"
+	"go-ddd-architecture/app/domain/player"
+	"go-ddd-architecture/app/domain/task"
+)
+
+// Test first-run behavior: Load on an empty collection should not error and leaves timestamps zero
+// (the use case seeds the close time from its injected clock).
+func TestRepository_FirstRun_LoadLeavesTimestampsZero(t *testing.T) {
+	r := New(NewMemoryDriver(), "")
+	_, ts, err := r.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !ts.WallClockAtClose.IsZero() {
+		t.Fatalf("expected zero timestamps on first run, got %v", ts.WallClockAtClose)
+	}
+}
+
+// Test roundtrip and profile isolation against the in-process fake driver.
+func TestRepository_Roundtrip_Profiles(t *testing.T) {
+	drv := NewMemoryDriver()
+	alice := New(drv, "alice")
+	bob := alice.ForProfile("bob")
+
+	p := player.Player{ID: "p1", CurrentLanguage: "go", Skills: map[string]player.Skill{"go": {Knowledge: 42, Level: 2}}, GPUs: 1}
+	ts := gametime.Timestamps{WallClockAtClose: time.Unix(1700000000, 0).UTC()}
+	if err := alice.Save(p, ts); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	// 寫入後修改原物件不應影響已存文件
+	p.Skills["go"] = player.Skill{}
+
+	p2, ts2, err := alice.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if p2.ID != "p1" || p2.GPUs != 1 || p2.Skills["go"].Knowledge != 42 {
+		t.Fatalf("player mismatch: %+v", p2)
+	}
+	if !ts2.WallClockAtClose.Equal(ts.WallClockAtClose) {
+		t.Fatalf("timestamps mismatch: %v != %v", ts2.WallClockAtClose, ts.WallClockAtClose)
+	}
+
+	if pb, _, _ := bob.Load(); pb.ID != "" {
+		t.Fatalf("profiles should be isolated, got %+v", pb)
+	}
+	_ = bob.Save(player.Player{ID: "p2"}, ts)
+	names, err := alice.Profiles()
+	if err != nil || len(names) != 2 || names[0] != "alice" || names[1] != "bob" {
+		t.Fatalf("unexpected profiles: %v err=%v", names, err)
+	}
+}
+
+// 進行中的任務與佇列需在 BSON 存讀後保留（fake 與真實 driver 使用相同編碼）。
+func TestRepository_Roundtrip_ActiveTask(t *testing.T) {
+	r := New(NewMemoryDriver(), "")
+	start := time.Unix(1700000000, 0).UTC()
+	p := player.Player{CurrentLanguage: "go"}
+	if err := p.StartPractice(start); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := p.EnqueueTask("Deploy"); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if err := r.Save(p, gametime.Timestamps{WallClockAtClose: start}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	got, _, err := r.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got.Current == nil || !got.Current.IsActive() || !got.Current.DoneAt().Equal(p.Current.DoneAt()) {
+		t.Fatalf("active task lost: %+v", got.Current)
+	}
+	if len(got.Queue) != 1 {
+		t.Fatalf("queue lost: %+v", got.Queue)
+	}
+}
+
+// 存檔文件以固定欄位名稱保存玩家狀態，可直接查詢；所有欄位存讀後不變。
+func TestRepository_DocumentFields(t *testing.T) {
+	drv := NewMemoryDriver()
+	r := New(drv, "")
+	at := time.Unix(1700000000, 0).UTC()
+	p := player.Player{
+		ID:              "p1",
+		LastSeen:        at,
+		Prestige:        1,
+		CurrentLanguage: "go",
+		Skills:          map[string]player.Skill{"go": {Knowledge: 42, Research: 7, Level: 3}},
+		Servers:         2,
+		GPUs:            1,
+		ProductionCarry: 1500 * time.Millisecond,
+		Automation:      player.Automation{Enabled: true, AutoPlay: true},
+		Queue:           []task.Type{task.Deploy},
+	}
+	if err := p.StartPractice(at); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := r.Save(p, gametime.Timestamps{WallClockAtClose: at}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	got, _, err := r.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !reflect.DeepEqual(got, p) {
+		t.Fatalf("roundtrip mismatch:\n got %+v\nwant %+v", got, p)
+	}
+
+	var raw struct {
+		Player struct {
+			GPUs   int `bson:"gpus"`
+			Skills map[string]struct {
+				Knowledge int64 `bson:"knowledge"`
+			} `bson:"skills"`
+			Current struct {
+				Active bool `bson:"active"`
+			} `bson:"current"`
+		} `bson:"player"`
+	}
+	if err := drv.Collection(collectionSaves).FindOne(context.Background(), DefaultProfile, &raw); err != nil {
+		t.Fatalf("raw find: %v", err)
+	}
+	if raw.Player.GPUs != 1 || raw.Player.Skills["go"].Knowledge != 42 || !raw.Player.Current.Active {
+		t.Fatalf("expected queryable player fields, got %+v", raw.Player)
+	}
+}
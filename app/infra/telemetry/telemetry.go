@@ -0,0 +1,114 @@
+// Package telemetry 以 internal/metrics 實作用例的觀測：儲存延遲與任務事件。
+package telemetry
+
+import (
+	"time"
+
+	"go-ddd-architecture/app/domain/gametime"
+	"go-ddd-architecture/app/domain/player"
+	dto "go-ddd-architecture/app/usecase/dto/game"
+	outPort "go-ddd-architecture/app/usecase/port/out/game"
+	"go-ddd-architecture/internal/metrics"
+)
+
+// repoBuckets 涵蓋記憶體（微秒級）到遠端資料庫（數百毫秒）的儲存延遲。
+var repoBuckets = []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1, 5}
+
+// WrapRepository 回傳記錄 Load/Save 延遲與錯誤數的 Repository；
+// 原實作支援 CommandLogger 時回傳值也支援（用例以型別斷言選擇 SaveCommand）。
+func WrapRepository(repo outPort.Repository, reg *metrics.Registry) outPort.Repository {
+	r := repoMetrics{
+		inner:    repo,
+		duration: reg.NewHistogram("repository_operation_duration_seconds", "Repository load/save latency.", repoBuckets, "op"),
+		errors:   reg.NewCounter("repository_operation_errors_total", "Failed repository operations.", "op"),
+	}
+	if cl, ok := repo.(outPort.CommandLogger); ok {
+		return commandRepoMetrics{repoMetrics: r, logger: cl}
+	}
+	return r
+}
+
+type repoMetrics struct {
+	inner    outPort.Repository
+	duration *metrics.HistogramVec
+	errors   *metrics.CounterVec
+}
+
+func (r repoMetrics) observe(op string, start time.Time, err error) {
+	r.duration.Observe(time.Since(start).Seconds(), op)
+	if err != nil {
+		r.errors.Inc(op)
+	}
+}
+
+func (r repoMetrics) Load() (player.Player, gametime.Timestamps, error) {
+	start := time.Now()
+	p, ts, err := r.inner.Load()
+	r.observe("load", start, err)
+	return p, ts, err
+}
+
+func (r repoMetrics) Save(p player.Player, ts gametime.Timestamps) error {
+	start := time.Now()
+	err := r.inner.Save(p, ts)
+	r.observe("save", start, err)
+	return err
+}
+
+type commandRepoMetrics struct {
+	repoMetrics
+	logger outPort.CommandLogger
+}
+
+func (r commandRepoMetrics) SaveCommand(cmd outPort.Command, p player.Player, ts gametime.Timestamps) error {
+	start := time.Now()
+	err := r.logger.SaveCommand(cmd, p, ts)
+	r.observe("save", start, err)
+	return err
+}
+
+// TaskMetrics 實作 outPort.TaskObserver，依任務型別與結果計數。
+type TaskMetrics struct {
+	started  *metrics.CounterVec
+	finished *metrics.CounterVec
+}
+
+func NewTaskMetrics(reg *metrics.Registry) *TaskMetrics {
+	return &TaskMetrics{
+		started:  reg.NewCounter("game_tasks_started_total", "Tasks started, by type.", "type"),
+		finished: reg.NewCounter("game_tasks_finished_total", "Tasks finished, by type and outcome.", "type", "outcome"),
+	}
+}
+
+func (m *TaskMetrics) TaskStarted(taskType string) { m.started.Inc(taskType) }
+
+func (m *TaskMetrics) TaskFinished(taskType string, success bool) {
+	outcome := "failure"
+	if success {
+		outcome = "success"
+	}
+	m.finished.Inc(taskType, outcome)
+}
+
+var _ outPort.TaskObserver = (*TaskMetrics)(nil)
+
+// RegisterEconomyGauges 註冊遊戲資源 gauge；每次抓取只呼叫一次 viewModel，所有數值來自同一份狀態。
+func RegisterEconomyGauges(reg *metrics.Registry, viewModel func() dto.ViewModelDto) {
+	lang := []string{"language"}
+	reg.NewGaugeSet([]metrics.GaugeDesc{
+		{Name: "game_language_knowledge", Help: "Knowledge per language.", Labels: lang},
+		{Name: "game_language_research", Help: "Research per language.", Labels: lang},
+		{Name: "game_language_level", Help: "Knowledge level per language.", Labels: lang},
+		{Name: "game_servers", Help: "Owned servers."},
+		{Name: "game_gpus", Help: "Owned GPUs."},
+	}, func(emit func(string, float64, ...string)) {
+		vm := viewModel()
+		for l, s := range vm.Languages {
+			emit("game_language_knowledge", float64(s.Knowledge), l)
+			emit("game_language_research", float64(s.Research), l)
+			emit("game_language_level", float64(s.Level), l)
+		}
+		emit("game_servers", float64(vm.Servers))
+		emit("game_gpus", float64(vm.GPUs))
+	})
+}
@@ -0,0 +1,95 @@
+package telemetry
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"go-ddd-architecture/app/domain/gametime"
+	"go-ddd-architecture/app/domain/player"
+	"go-ddd-architecture/app/infra/memory"
+	dto "go-ddd-architecture/app/usecase/dto/game"
+	outPort "go-ddd-architecture/app/usecase/port/out/game"
+	"go-ddd-architecture/internal/metrics"
+)
+
+// loggingRepo 支援 CommandLogger，且可切換失敗。
+type loggingRepo struct {
+	*memory.InMemoryRepo
+	commands []string
+	fail     bool
+}
+
+func (r *loggingRepo) SaveCommand(cmd outPort.Command, p player.Player, ts gametime.Timestamps) error {
+	if r.fail {
+		return errors.New("disk full")
+	}
+	r.commands = append(r.commands, cmd.Name)
+	return r.Save(p, ts)
+}
+
+func TestWrapRepository_PreservesCommandLogger(t *testing.T) {
+	reg := metrics.NewRegistry()
+	if _, ok := WrapRepository(memory.NewInMemoryRepo(), metrics.NewRegistry()).(outPort.CommandLogger); ok {
+		t.Fatalf("plain repository must not gain SaveCommand")
+	}
+	inner := &loggingRepo{InMemoryRepo: memory.NewInMemoryRepo()}
+	repo := WrapRepository(inner, reg)
+	cl, ok := repo.(outPort.CommandLogger)
+	if !ok {
+		t.Fatalf("CommandLogger lost by wrapper")
+	}
+	if err := cl.SaveCommand(outPort.Command{Name: "buy-gpu"}, player.Player{}, gametime.Timestamps{}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	inner.fail = true
+	_ = cl.SaveCommand(outPort.Command{Name: "buy-gpu"}, player.Player{}, gametime.Timestamps{})
+	if _, _, err := repo.Load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(inner.commands) != 1 || inner.commands[0] != "buy-gpu" {
+		t.Fatalf("command not forwarded: %v", inner.commands)
+	}
+
+	var buf bytes.Buffer
+	if err := reg.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`repository_operation_duration_seconds_count{op="save"} 2`,
+		`repository_operation_duration_seconds_count{op="load"} 1`,
+		`repository_operation_errors_total{op="save"} 1`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, buf.String())
+		}
+	}
+}
+
+// 每次抓取只取得一次 ViewModel，所有資源 gauge 來自同一份狀態。
+func TestRegisterEconomyGauges_OneViewModelPerScrape(t *testing.T) {
+	reg := metrics.NewRegistry()
+	calls := 0
+	RegisterEconomyGauges(reg, func() dto.ViewModelDto {
+		calls++
+		return dto.ViewModelDto{Servers: 2, GPUs: 1, Languages: map[string]dto.LanguageStats{"go": {Knowledge: 40, Research: 5, Level: 3}}}
+	})
+	var buf bytes.Buffer
+	if err := reg.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected one ViewModel per scrape, got %d", calls)
+	}
+	for _, want := range []string{
+		`game_language_knowledge{language="go"} 40`,
+		`game_language_level{language="go"} 3`,
+		"game_servers 2",
+		"game_gpus 1",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, buf.String())
+		}
+	}
+}
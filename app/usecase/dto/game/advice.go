@@ -0,0 +1,48 @@
+package game
+
+import "encoding/json"
+
+// AdviceDto 為進度預測與依 ROI 排序的建議（最佳在前）。
+type AdviceDto struct {
+	Forecast        ForecastDto
+	Recommendations []RecommendationDto
+}
+
+// ForecastDto 以目前收入（被動產出 + 持續進行最佳任務）預測進度；分鐘數 -1 表示無法預測。
+type ForecastDto struct {
+	Language        string
+	KnowledgePerMin float64
+	ResearchPerMin  float64
+	UpgradeCost     int64
+	UpgradeMinutes  float64
+	Store           []ItemForecastDto
+}
+
+type ItemForecastDto struct {
+	Item          string
+	CostKnowledge int64
+	Minutes       float64
+	Blocked       string `json:",omitempty"`
+}
+
+type RecommendationDto struct {
+	// upgrade | buy-server | buy-gpu | switch-language
+	Action   string
+	Language string `json:",omitempty"`
+	// 可直接送至 /commands 的命令
+	Command string
+	Args    json.RawMessage
+
+	CostKnowledge int64
+	CostResearch  int64
+	Affordable    bool
+	WaitMinutes   float64
+	Blocked       string `json:",omitempty"`
+
+	DeltaKnowledgePerMin float64
+	DeltaResearchPerMin  float64
+	KnowledgeROI         float64
+	ResearchROI          float64
+	Score                float64
+	Note                 string `json:",omitempty"`
+}
@@ -0,0 +1,37 @@
+package game
+
+import "encoding/json"
+
+// CommandResultDto 為單一命令的執行結果。
+type CommandResultDto struct {
+	Command string
+	// TryFinish：此次推進是否有任務結算及其獎勵總和
+	Finished bool
+	Reward   int64
+}
+
+// AuditEntryDto 為一筆命令稽核紀錄。
+type AuditEntryDto struct {
+	// 執行時間（RFC3339 UTC）
+	At      string
+	Command string
+	Args    json.RawMessage
+	OK      bool
+	// 失敗原因（驗證、規則或存檔錯誤）
+	Error string `json:",omitempty"`
+}
+
+// BatchStepDto 為批次中單一步驟的結果。
+type BatchStepDto struct {
+	Index  int
+	Result CommandResultDto
+	OK     bool
+	// 失敗原因；因前面步驟失敗而未執行者為 "skipped"
+	Error string `json:",omitempty"`
+}
+
+// BatchResultDto 為批次執行結果；Applied 為 false 時所有步驟皆未生效。
+type BatchResultDto struct {
+	Applied bool
+	Steps   []BatchStepDto
+}
@@ -0,0 +1,33 @@
+package game
+
+// ExplainDto 為各任務類型在目前狀態下的成功率、時長與獎勵拆解。
+type ExplainDto struct {
+	Language string
+	Tasks    []TaskExplainDto
+}
+
+// FactorDto 為一個組成項；Value 為此項的貢獻量（成功率為 0~1，時長為秒或縮短比例）。
+type FactorDto struct {
+	Name   string
+	Value  float64
+	Detail string
+}
+
+type TaskExplainDto struct {
+	Type           string
+	Success        float64
+	SuccessFactors []FactorDto
+
+	BaseDurationSeconds float64
+	DurationSeconds     float64
+	DurationFactors     []FactorDto
+
+	// 成功時的 Knowledge 獎勵與 Research 獎勵範圍
+	Reward        int64
+	ResearchMin   int64
+	ResearchMax   int64
+	RewardFactors []FactorDto
+	// 期望值（已乘上成功率）
+	ExpectedKnowledge float64
+	ExpectedResearch  float64
+}
@@ -0,0 +1,17 @@
+package game
+
+// HistoryDto 為統計時間序列的展示資料（供圖表使用）。
+type HistoryDto struct {
+	Resolution string
+	Points     []HistoryPoint
+}
+
+type HistoryPoint struct {
+	// 桶起點（RFC3339 UTC 字串）
+	At              string
+	Languages       map[string]LanguageStats
+	KnowledgePerMin int64
+	ResearchPerMin  int64
+	Servers         int
+	GPUs            int
+}
@@ -2,9 +2,10 @@ package game
 
 // ViewModelDto 為最小展示資料，用於 CLI/UI。
 type ViewModelDto struct {
-	Knowledge       int64
-	Research        int64
-	Notices         []string
+	Knowledge int64
+	Research  int64
+	// Notices 為尚未確認的通知（最新在前），確認後或過期即移除
+	Notices         []NoticeDto
 	CurrentTask     *TaskInfo
 	Level           int
 	NextUpgradeCost int64
@@ -22,6 +23,30 @@ type ViewModelDto struct {
 	Servers int
 	GPUs    int
 	Slots   int // total slots = Servers * SlotsPerServer
+
+	// --- Automation ---
+	Automation AutomationInfo
+	// Queue 為目前任務結束後依序啟動的任務類型
+	Queue []string
+}
+
+type NoticeDto struct {
+	ID string
+	// info | warning | error
+	Severity string
+	Message  string
+	// 合併的重複次數
+	Count int
+	// 最近一次發生時間（RFC3339 UTC）
+	At string
+	// 過期時間（RFC3339 UTC）；空字串表示需確認才會移除
+	ExpiresAt string
+}
+
+type AutomationInfo struct {
+	Enabled      bool
+	AutoPractice bool
+	AutoPlay     bool
 }
 
 type TaskInfo struct {
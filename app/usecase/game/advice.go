@@ -0,0 +1,109 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go-ddd-architecture/app/domain/advisor"
+	"go-ddd-architecture/app/domain/player"
+	dto "go-ddd-architecture/app/usecase/dto/game"
+	inPort "go-ddd-architecture/app/usecase/port/in/game"
+)
+
+// maxAutoPlay 為單次推進中自動遊玩最多執行的動作數（避免異常時無限循環）。
+const maxAutoPlay = 10
+
+// Advice 回傳進度預測與依 ROI 排序的建議；每項附上可直接送至 /commands 的命令。
+func (uc *Interactor) Advice() dto.AdviceDto {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	r := advisor.Advise(uc.p)
+	out := dto.AdviceDto{Forecast: dto.ForecastDto{
+		Language:        r.Forecast.Language,
+		KnowledgePerMin: r.Forecast.Rates.KnowledgePerMin,
+		ResearchPerMin:  r.Forecast.Rates.ResearchPerMin,
+		UpgradeCost:     r.Forecast.UpgradeCost,
+		UpgradeMinutes:  r.Forecast.UpgradeMinutes,
+	}}
+	for _, it := range r.Forecast.Store {
+		out.Forecast.Store = append(out.Forecast.Store, dto.ItemForecastDto{
+			Item: string(it.Kind), CostKnowledge: it.CostKnowledge, Minutes: it.Minutes, Blocked: it.Blocked,
+		})
+	}
+	for _, a := range r.Recommendations {
+		cmd, err := actionCommand(a.Action)
+		if err != nil {
+			continue
+		}
+		args, _ := json.Marshal(cmd)
+		out.Recommendations = append(out.Recommendations, dto.RecommendationDto{
+			Action:               string(a.Action.Kind),
+			Language:             a.Action.Lang,
+			Command:              cmd.CommandName(),
+			Args:                 args,
+			CostKnowledge:        a.CostKnowledge,
+			CostResearch:         a.CostResearch,
+			Affordable:           a.Affordable,
+			WaitMinutes:          a.WaitMinutes,
+			Blocked:              a.Blocked,
+			DeltaKnowledgePerMin: a.DeltaKnowledgePerMin,
+			DeltaResearchPerMin:  a.DeltaResearchPerMin,
+			KnowledgeROI:         a.KnowledgeROI,
+			ResearchROI:          a.ResearchROI,
+			Score:                a.Score,
+			Note:                 a.Note,
+		})
+	}
+	return out
+}
+
+// autoPlay 於啟用自動遊玩時，依顧問策略在副本上連續執行可負擔的最佳動作，回傳已執行的命令。
+func (uc *Interactor) autoPlay(p *player.Player, now time.Time) []inPort.Command {
+	if !p.Automation.AutoPlay {
+		return nil
+	}
+	var played []inPort.Command
+	for i := 0; i < maxAutoPlay; i++ {
+		a, ok := advisor.GreedyROI.Next(*p)
+		if !ok {
+			break
+		}
+		// 無法對應命令的建議不可退回其他動作；策略會再次給出同一建議，因此直接停止
+		cmd, err := actionCommand(a)
+		if err != nil {
+			break
+		}
+		if _, err := apply(p, cmd, now, player.AdvanceResult{}); err != nil {
+			break
+		}
+		played = append(played, cmd)
+	}
+	return played
+}
+
+// actionCommand 將顧問動作轉為對應的命令（與 /commands 共用同一套套用流程）；
+// 未知的動作類型回傳 advisor.ErrUnknownAction。
+func actionCommand(a advisor.Action) (inPort.Command, error) {
+	switch a.Kind {
+	case advisor.Upgrade:
+		return inPort.Upgrade{}, nil
+	case advisor.BuyServer:
+		return inPort.Buy{Item: inPort.ItemServer}, nil
+	case advisor.BuyGPU:
+		return inPort.Buy{Item: inPort.ItemGPU}, nil
+	case advisor.Switch:
+		return inPort.SelectLanguage{Lang: a.Lang}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", advisor.ErrUnknownAction, a.Kind)
+}
+
+func describe(cmd inPort.Command) string {
+	switch c := cmd.(type) {
+	case inPort.Buy:
+		return "buy " + c.Item
+	case inPort.SelectLanguage:
+		return "switch to " + c.Lang
+	}
+	return cmd.CommandName()
+}
@@ -0,0 +1,191 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"go-ddd-architecture/app/domain/player"
+	"go-ddd-architecture/app/domain/task"
+	dto "go-ddd-architecture/app/usecase/dto/game"
+	inPort "go-ddd-architecture/app/usecase/port/in/game"
+	outPort "go-ddd-architecture/app/usecase/port/out/game"
+)
+
+// maxAudit 為保留的稽核紀錄筆數（僅存於記憶體，超過時捨棄最舊者）。
+const maxAudit = 200
+
+// Dispatch 以現在時間執行命令。
+func (uc *Interactor) Dispatch(cmd inPort.Command) (dto.CommandResultDto, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	return uc.dispatch(cmd, uc.clk.Now())
+}
+
+// Audit 回傳最近的命令稽核紀錄（最新在前）。
+func (uc *Interactor) Audit() []dto.AuditEntryDto {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	out := make([]dto.AuditEntryDto, 0, len(uc.audit))
+	for i := len(uc.audit) - 1; i >= 0; i-- {
+		out = append(out, uc.audit[i])
+	}
+	return out
+}
+
+// DispatchBatch 於同一副本上依序套用命令：全部成功才單次持久化，任一失敗則整批捨棄。
+func (uc *Interactor) DispatchBatch(cmds []inPort.Command) (dto.BatchResultDto, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if len(cmds) == 0 || len(cmds) > inPort.MaxBatch {
+		return dto.BatchResultDto{}, fmt.Errorf("%w: batch must contain 1..%d commands", inPort.ErrInvalidCommand, inPort.MaxBatch)
+	}
+	now := uc.clk.Now()
+	before := uc.p
+	p := uc.p.Clone()
+	adv := uc.advance(&p, now)
+	out := dto.BatchResultDto{Steps: make([]dto.BatchStepDto, len(cmds))}
+	args := make([]json.RawMessage, len(cmds))
+	for i, cmd := range cmds {
+		args[i], _ = json.Marshal(cmd)
+		res, err := apply(&p, cmd, now, adv)
+		// 推進結果只歸屬於第一個需要它的步驟（避免 TryFinish 重複計算獎勵）
+		if _, ok := cmd.(inPort.TryFinish); ok {
+			adv = player.AdvanceResult{}
+		}
+		out.Steps[i] = dto.BatchStepDto{Index: i, Result: res, OK: err == nil}
+		if err != nil {
+			out.Steps[i].Error = err.Error()
+			for j := i + 1; j < len(cmds); j++ {
+				out.Steps[j] = dto.BatchStepDto{Index: j, Result: dto.CommandResultDto{Command: cmds[j].CommandName()}, Error: "skipped"}
+			}
+			uc.record(cmd.CommandName(), args[i], now, err)
+			return out, &inPort.BatchError{Index: i, Err: uc.reject(err)}
+		}
+	}
+	batch := make([]map[string]any, len(cmds))
+	for i, cmd := range cmds {
+		batch[i] = map[string]any{"command": cmd.CommandName(), "args": args[i]}
+	}
+	arg, _ := json.Marshal(batch)
+	if err := uc.commit(outPort.Command{Name: "batch", Arg: string(arg)}, p, uc.ts); err != nil {
+		uc.record("batch", arg, now, err)
+		return out, err
+	}
+	for i, cmd := range cmds {
+		uc.record(cmd.CommandName(), args[i], now, nil)
+	}
+	uc.noteUpgrades(before)
+	out.Applied = true
+	return out, nil
+}
+
+func (uc *Interactor) dispatchAt(cmd inPort.Command, now time.Time) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	_, err := uc.dispatch(cmd, now)
+	return err
+}
+
+// dispatch 為所有命令的統一流程：推進副本至 now → 驗證並套用 → 持久化 → 稽核。
+// 驗證或規則錯誤不會持久化，並記為通知；呼叫端需持有 mu。
+func (uc *Interactor) dispatch(cmd inPort.Command, now time.Time) (dto.CommandResultDto, error) {
+	args, _ := json.Marshal(cmd)
+	before := uc.p
+	p := uc.p.Clone()
+	res := uc.advance(&p, now)
+	out, err := apply(&p, cmd, now, res)
+	if err != nil {
+		uc.record(cmd.CommandName(), args, now, err)
+		return out, uc.reject(err)
+	}
+	if err := uc.commit(outPort.Command{Name: cmd.CommandName(), Arg: string(args)}, p, uc.ts); err != nil {
+		uc.record(cmd.CommandName(), args, now, err)
+		return dto.CommandResultDto{}, err
+	}
+	uc.record(cmd.CommandName(), args, now, nil)
+	uc.noteUpgrades(before)
+	return out, nil
+}
+
+// noteUpgrades 比對提交前後各語言等級，為升級的語言新增通知。
+// before 為提交前的快取；commit 以新副本取代快取，不會改動其 Skills。
+func (uc *Interactor) noteUpgrades(before player.Player) {
+	langs := make([]string, 0, len(uc.p.Skills))
+	for lang := range uc.p.Skills {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	for _, lang := range langs {
+		if s := uc.p.Skills[lang]; s.Level > before.Skills[lang].Level {
+			uc.addNotice("upgrade:"+lang, SeverityInfo, fmt.Sprintf("%s reached Lv %d", lang, s.Level))
+		}
+	}
+}
+
+// record 追加一筆稽核紀錄。
+func (uc *Interactor) record(name string, args json.RawMessage, now time.Time, err error) {
+	e := dto.AuditEntryDto{At: now.UTC().Format(time.RFC3339), Command: name, Args: args, OK: err == nil}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	uc.audit = append(uc.audit, e)
+	if len(uc.audit) > maxAudit {
+		uc.audit = uc.audit[len(uc.audit)-maxAudit:]
+	}
+}
+
+// apply 驗證命令並套用到玩家副本；adv 為套用前推進至 now 的結算結果。
+func apply(p *player.Player, cmd inPort.Command, now time.Time, adv player.AdvanceResult) (dto.CommandResultDto, error) {
+	out := dto.CommandResultDto{Command: cmd.CommandName()}
+	switch c := cmd.(type) {
+	case inPort.StartTask:
+		if err := selectIfSet(p, c.Lang); err != nil {
+			return out, err
+		}
+		return out, p.StartTask(task.Type(c.Type), now)
+	case inPort.Enqueue:
+		if err := p.EnqueueTask(task.Type(c.Type)); err != nil {
+			return out, err
+		}
+		p.StartNext(now)
+	case inPort.Upgrade:
+		if err := selectIfSet(p, c.Lang); err != nil {
+			return out, err
+		}
+		return out, p.UpgradeKnowledge()
+	case inPort.Buy:
+		switch c.Item {
+		case inPort.ItemServer:
+			return out, p.BuyServer()
+		case inPort.ItemGPU:
+			return out, p.BuyGPU()
+		}
+		return out, fmt.Errorf("%w: unknown item %q", inPort.ErrInvalidCommand, c.Item)
+	case inPort.SelectLanguage:
+		return out, p.SelectLanguage(c.Lang)
+	case inPort.SetAutomation:
+		autoPlay := p.Automation.AutoPlay
+		if c.AutoPlay != nil {
+			autoPlay = *c.AutoPlay
+		}
+		p.Automation = player.Automation{Enabled: c.Enabled, AutoPractice: c.AutoPractice, AutoPlay: autoPlay}
+		p.StartNext(now)
+	case inPort.TryFinish:
+		for _, f := range adv.Finished {
+			out.Finished = true
+			out.Reward += f.Reward
+		}
+	default:
+		return out, fmt.Errorf("%w: unsupported command %T", inPort.ErrInvalidCommand, cmd)
+	}
+	return out, nil
+}
+
+func selectIfSet(p *player.Player, lang string) error {
+	if lang == "" || lang == p.CurrentLanguage {
+		return nil
+	}
+	return p.SelectLanguage(lang)
+}
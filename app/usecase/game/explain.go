@@ -0,0 +1,48 @@
+package game
+
+import (
+	"go-ddd-architecture/app/domain/player"
+	dto "go-ddd-architecture/app/usecase/dto/game"
+)
+
+// Explain 回傳各任務類型在目前狀態（或指定語言）下的成功率、時長與獎勵拆解；不改變狀態。
+func (uc *Interactor) Explain(lang string) (dto.ExplainDto, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	// 於副本上計算：領域方法可能初始化 Skills，且指定語言時需切換
+	p := uc.p.Clone()
+	if err := selectIfSet(&p, lang); err != nil {
+		return dto.ExplainDto{}, err
+	}
+	out := dto.ExplainDto{}
+	for _, tt := range player.TaskTypes {
+		e, err := p.Explain(tt)
+		if err != nil {
+			return dto.ExplainDto{}, err
+		}
+		out.Language = e.Language
+		out.Tasks = append(out.Tasks, dto.TaskExplainDto{
+			Type:                string(e.Type),
+			Success:             e.Success,
+			SuccessFactors:      factorDtos(e.SuccessFactors),
+			BaseDurationSeconds: e.BaseDuration.Seconds(),
+			DurationSeconds:     e.Duration.Seconds(),
+			DurationFactors:     factorDtos(e.DurationFactors),
+			Reward:              e.Reward,
+			ResearchMin:         e.ResearchMin,
+			ResearchMax:         e.ResearchMax,
+			RewardFactors:       factorDtos(e.RewardFactors),
+			ExpectedKnowledge:   e.ExpectedReward,
+			ExpectedResearch:    e.ExpectedResearch,
+		})
+	}
+	return out, nil
+}
+
+func factorDtos(fs []player.Factor) []dto.FactorDto {
+	out := make([]dto.FactorDto, len(fs))
+	for i, f := range fs {
+		out[i] = dto.FactorDto{Name: f.Name, Value: f.Value, Detail: f.Detail}
+	}
+	return out
+}
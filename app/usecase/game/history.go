@@ -0,0 +1,77 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"go-ddd-architecture/app/domain/stats"
+	dto "go-ddd-architecture/app/usecase/dto/game"
+	outPort "go-ddd-architecture/app/usecase/port/out/game"
+)
+
+// viewModelSource 只取用 Interactor 的唯讀展示資料，避免取樣器碰觸聚合本身。
+type viewModelSource interface {
+	GetViewModel() dto.ViewModelDto
+}
+
+// HistoryService 將目前狀態取樣成時間序列，並提供查詢。
+type HistoryService struct {
+	src   viewModelSource
+	store outPort.HistoryStore
+	clk   Clock
+}
+
+func NewHistoryService(src viewModelSource, store outPort.HistoryStore, clk Clock) *HistoryService {
+	return &HistoryService{src: src, store: store, clk: clk}
+}
+
+// Sample 取樣目前各語言進度、產率與硬體數量。
+func (hs *HistoryService) Sample() error {
+	vm := hs.src.GetViewModel()
+	s := stats.Sample{
+		At:              hs.clk.Now().UTC(),
+		Languages:       make(map[string]stats.LangSample, len(vm.Languages)),
+		KnowledgePerMin: vm.KnowledgePerMin,
+		ResearchPerMin:  vm.ResearchPerMin,
+		Servers:         vm.Servers,
+		GPUs:            vm.GPUs,
+	}
+	for k, l := range vm.Languages {
+		s.Languages[k] = stats.LangSample{Knowledge: l.Knowledge, Research: l.Research, Level: l.Level}
+	}
+	return hs.store.Record(s)
+}
+
+// History 查詢時間序列。
+func (hs *HistoryService) History(res string, from, to time.Time) (dto.HistoryDto, error) {
+	r, ok := stats.ParseResolution(res)
+	if !ok {
+		return dto.HistoryDto{}, fmt.Errorf("unknown resolution %q", res)
+	}
+	if to.IsZero() {
+		to = hs.clk.Now().UTC()
+	}
+	if from.IsZero() {
+		from = to.Add(-r.DefaultWindow())
+	}
+	samples, err := hs.store.Query(r, from, to)
+	if err != nil {
+		return dto.HistoryDto{}, err
+	}
+	out := dto.HistoryDto{Resolution: string(r), Points: make([]dto.HistoryPoint, 0, len(samples))}
+	for _, s := range samples {
+		pt := dto.HistoryPoint{
+			At:              s.At.UTC().Format(time.RFC3339),
+			Languages:       make(map[string]dto.LanguageStats, len(s.Languages)),
+			KnowledgePerMin: s.KnowledgePerMin,
+			ResearchPerMin:  s.ResearchPerMin,
+			Servers:         s.Servers,
+			GPUs:            s.GPUs,
+		}
+		for k, l := range s.Languages {
+			pt.Languages[k] = dto.LanguageStats{Knowledge: l.Knowledge, Research: l.Research, Level: l.Level}
+		}
+		out.Points = append(out.Points, pt)
+	}
+	return out, nil
+}
@@ -1,37 +1,72 @@
 package game
 
 import (
+	"encoding/json"
+	"fmt"
+	"sync"
 	"time"
 
 	"go-ddd-architecture/app/domain/gametime"
 	"go-ddd-architecture/app/domain/player"
+	"go-ddd-architecture/app/domain/task"
 	dto "go-ddd-architecture/app/usecase/dto/game"
+	inPort "go-ddd-architecture/app/usecase/port/in/game"
 	outPort "go-ddd-architecture/app/usecase/port/out/game"
 )
 
-// Clock 由外部注入，利於測試。
-type Clock interface{ Now() time.Time }
+// Clock 由外部注入，利於測試（定義於 out port）。
+type Clock = outPort.Clock
 
 // Interactor 將領域服務與儲存協調起來。
+// net/http 會併發呼叫各方法，因此所有存取快取狀態的方法皆以 mu 串行化。
 type Interactor struct {
 	repo outPort.Repository
 	clk  Clock
 	calc *gametime.OfflineCalculator
 
+	// mu 保護下方快取狀態；注意領域讀取方法（如 EstimatedSuccess）也可能寫入 Skills map。
+	mu sync.Mutex
 	// 快取狀態（載入於 Initialize）
 	p  player.Player
 	ts gametime.Timestamps
+	// initial 為 Initialize 時自動結算的離線結果
+	initial gametime.OfflineResult
+
+	changes changeNotifier
+	// version 為狀態版本：每次 ViewModel 可能改變（提交、通知增減）時遞增，供推播續傳與條件式請求使用
+	version uint64
+	notices noticeCenter
+	// pending 為本次推進中結算、待提交成功後轉為通知的任務結果
+	pending []player.TaskOutcome
+	// audit 為最近的命令稽核紀錄（Dispatch 統一寫入）
+	audit []dto.AuditEntryDto
+	// observer 於提交成功後收到任務開始與結算（選用）
+	observer outPort.TaskObserver
 }
 
 func NewInteractor(repo outPort.Repository, clk Clock, calc *gametime.OfflineCalculator) *Interactor {
 	return &Interactor{repo: repo, clk: clk, calc: calc}
 }
 
+// SetTaskObserver 設定任務觀測者；需於 Initialize 之前呼叫。
+func (uc *Interactor) SetTaskObserver(o outPort.TaskObserver) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.observer = o
+}
+
+// Initialize 載入存檔並自動以現在時間結算離線收益（從上次記錄的關閉時間起算）。
 func (uc *Interactor) Initialize() error {
-	p, ts, err := uc.repo.Load()
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	loaded, ts, err := uc.repo.Load()
 	if err != nil {
 		return err
 	}
+	// Load 的結果可能與 Repository 內部共用 map（例如記憶體實作），先複製再結算
+	p := loaded.Clone()
+	// 以存檔狀態作為提交前的基準，存檔中已進行的任務不計為此次開始
+	uc.p = loaded.Clone()
 	// 初始化多語言映射避免 nil map
 	if p.Skills == nil {
 		p.Skills = map[string]player.Skill{}
@@ -40,22 +75,275 @@ func (uc *Interactor) Initialize() error {
 	if p.CurrentLanguage == "" {
 		p.CurrentLanguage = "go"
 	}
-	uc.p = p
-	uc.ts = ts
+	now := uc.clk.Now()
+	// 首次啟動（從未記錄關閉時間）沒有離線區間：只以注入的時鐘對齊，不結算也不判為時間異常
+	var res gametime.OfflineResult
+	if ts.WallClockAtClose.IsZero() {
+		p.LastSeen = now
+	} else {
+		res = uc.calc.Compute(&p, ts, now)
+	}
+	ts.WallClockAtClose = now
+	uc.pending = res.Finished
+	if err := uc.commit(outPort.Command{Name: "initialize"}, p, ts); err != nil {
+		return err
+	}
+	uc.initial = res
+	uc.noteOffline(res)
 	return nil
 }
 
+// InitialOffline 回傳最近一次 Initialize 自動結算的離線結果。
+func (uc *Interactor) InitialOffline() gametime.OfflineResult {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	return uc.initial
+}
+
+// RecordClose 記錄目前時間為關閉時間（心跳與關機時呼叫），
+// 使下次啟動的離線結算從實際關閉的時間點起算；當機時最多損失一個心跳間隔。
+func (uc *Interactor) RecordClose(now time.Time) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	p := uc.p.Clone()
+	uc.advance(&p, now)
+	ts := uc.ts
+	ts.WallClockAtClose = now
+	return uc.commit(outPort.Command{Name: "record-close"}, p, ts)
+}
+
 func (uc *Interactor) ClaimOffline(now time.Time) (gametime.OfflineResult, error) {
-	res := uc.calc.Compute(&uc.p, uc.ts, now)
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	p, ts := uc.p.Clone(), uc.ts
+	// 線上推進已涵蓋到 LastSeen，離線區間自兩者較晚者起算，避免重複計入
+	from := ts
+	if p.LastSeen.After(from.WallClockAtClose) {
+		from.WallClockAtClose = p.LastSeen
+	}
+	res := uc.calc.Compute(&p, from, now)
 	// 更新 timestamps 的關閉時間供下次計算
-	uc.ts.WallClockAtClose = now
-	if err := uc.repo.Save(uc.p, uc.ts); err != nil {
+	ts.WallClockAtClose = now
+	uc.pending = res.Finished
+	if err := uc.commit(outPort.Command{Name: "claim-offline"}, p, ts); err != nil {
 		return res, err
 	}
+	uc.noteOffline(res)
+	return res, nil
+}
+
+// Advance 將遊戲狀態自上次推進點（Player.LastSeen）往前推進 dt。
+func (uc *Interactor) Advance(dt time.Duration) (player.AdvanceResult, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	p := uc.p.Clone()
+	from := p.LastSeen
+	if from.IsZero() {
+		from = uc.clk.Now().Add(-dt)
+	}
+	res := p.Advance(from, dt)
+	uc.pending = res.Finished
+	if err := uc.commit(outPort.Command{Name: "advance", Arg: dt.String()}, p, uc.ts); err != nil {
+		return player.AdvanceResult{}, err
+	}
+	return res, nil
+}
+
+// AdvanceTo 將遊戲狀態推進到 now（排程器在任務完成時間喚醒時使用）。
+func (uc *Interactor) AdvanceTo(now time.Time) (player.AdvanceResult, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	before := uc.p
+	p := uc.p.Clone()
+	res := uc.advance(&p, now)
+	played := uc.autoPlay(&p, now)
+	// 沒有任何變更時不存檔也不通知，避免排程器被自己的通知喚醒而空轉
+	if len(res.Finished) == 0 && len(played) == 0 && p.LastSeen.Equal(before.LastSeen) {
+		uc.pending = nil
+		return res, nil
+	}
+	if err := uc.commit(outPort.Command{Name: "advance-to"}, p, uc.ts); err != nil {
+		return player.AdvanceResult{}, err
+	}
+	for _, cmd := range played {
+		args, _ := json.Marshal(cmd)
+		uc.record(cmd.CommandName(), args, now, nil)
+		uc.addNotice("auto-play", SeverityInfo, "auto-play: "+describe(cmd))
+	}
+	uc.noteUpgrades(before)
 	return res, nil
 }
 
+// NextWake 回傳自動化下一次需要推進的時間點（目前任務的完成時間）。
+func (uc *Interactor) NextWake() (time.Time, bool) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	return uc.p.NextWake()
+}
+
+// advance 推進副本並暫存結算結果，待 commit 成功後轉為通知。
+func (uc *Interactor) advance(p *player.Player, now time.Time) player.AdvanceResult {
+	res := advanceTo(p, now)
+	uc.pending = res.Finished
+	return res
+}
+
+// advanceTo 將玩家推進到 now；首次推進時只對齊 LastSeen。
+// 時間未超過 LastSeen（時鐘倒退）時不產生被動產出，但仍結算完成時間已到的任務，
+// 否則 NextWake 會持續回傳過去的時間。
+func advanceTo(p *player.Player, now time.Time) player.AdvanceResult {
+	if p.LastSeen.IsZero() {
+		p.LastSeen = now
+		return player.AdvanceResult{}
+	}
+	if !now.After(p.LastSeen) {
+		return player.AdvanceResult{Finished: p.SettleTasks(now)}
+	}
+	return p.Advance(p.LastSeen, now.Sub(p.LastSeen))
+}
+
+// commit 先持久化副本，成功後才取代快取，確保記憶體與儲存不分歧。
+// 若 Repository 支援 CommandLogger，一併記錄造成變更的命令。
+func (uc *Interactor) commit(cmd outPort.Command, p player.Player, ts gametime.Timestamps) error {
+	var err error
+	if cl, ok := uc.repo.(outPort.CommandLogger); ok {
+		err = cl.SaveCommand(cmd, p, ts)
+	} else {
+		err = uc.repo.Save(p, ts)
+	}
+	finished := uc.pending
+	uc.pending = nil
+	if err != nil {
+		uc.addNotice("save-failed", SeverityError, "save failed: "+err.Error())
+		return err
+	}
+	uc.observeTasks(uc.p, p, finished)
+	uc.p = p
+	uc.ts = ts
+	for _, f := range finished {
+		if !f.Success {
+			uc.notices.add("task-failed:"+string(f.Type), SeverityWarning, fmt.Sprintf("%s task failed (%s)", f.Type, f.Language), uc.clk.Now())
+		}
+	}
+	uc.changed()
+	return nil
+}
+
+// observeTasks 比對提交前後的狀態回報任務事件：任務依序進行，因此此次結算的任務中
+// 除了提交前已在進行者之外都是此次開始的，提交後仍在進行的新任務亦同。
+// 任務 ID 為規格代碼（同型別相同），以完成時間區分不同次的任務。
+func (uc *Interactor) observeTasks(before, after player.Player, finished []player.TaskOutcome) {
+	if uc.observer == nil {
+		return
+	}
+	var prevDone time.Time
+	if before.Current != nil && before.Current.IsActive() {
+		prevDone = before.Current.DoneAt()
+	}
+	for _, f := range finished {
+		if !f.DoneAt.Equal(prevDone) {
+			uc.observer.TaskStarted(string(f.Type))
+		}
+		uc.observer.TaskFinished(string(f.Type), f.Success)
+	}
+	if after.Current != nil && after.Current.IsActive() && !after.Current.DoneAt().Equal(prevDone) {
+		uc.observer.TaskStarted(string(after.Current.Type))
+	}
+}
+
+// reject 將違反規則的領域錯誤記為通知後原樣回傳（狀態未變更）。
+// 錯誤已直接回給呼叫端，因此不遞增版本（ETag、長輪詢與推送不受影響），通知隨下一次狀態變更送出；
+// 同一拒絕仍在列表中時不再記錄，客戶端反覆收到 409 不會讓 ViewModel 持續變動。
+func (uc *Interactor) reject(err error) error {
+	key := "rejected:" + err.Error()
+	uc.expireNotices()
+	if !uc.notices.listed(key) {
+		uc.notices.add(key, SeverityWarning, err.Error(), uc.clk.Now())
+	}
+	return err
+}
+
+// noteOffline 將離線結算結果（收益或時間異常）記為通知。
+func (uc *Interactor) noteOffline(res gametime.OfflineResult) {
+	switch {
+	case res.AnomalyDetected:
+		uc.addNotice("time-anomaly", SeverityWarning, res.Message)
+	case res.GainedKnowledge > 0 || res.GainedResearch > 0:
+		msg := fmt.Sprintf("offline: +%d Knowledge, +%d Research", res.GainedKnowledge, res.GainedResearch)
+		if res.ClampedTo8h {
+			msg += " (capped at 8h)"
+		}
+		uc.addNotice("offline", SeverityInfo, msg)
+	}
+}
+
+// addNotice 新增通知並通知訂閱者（ViewModel 已變更）。
+func (uc *Interactor) addNotice(key string, sev Severity, msg string) {
+	uc.notices.add(key, sev, msg, uc.clk.Now())
+	uc.changed()
+}
+
+// AckNotice 確認（移除）指定通知。
+func (uc *Interactor) AckNotice(id string) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if !uc.notices.ack(id) {
+		return inPort.ErrNoticeNotFound
+	}
+	uc.changed()
+	return nil
+}
+
+// AckAllNotices 確認所有通知。
+func (uc *Interactor) AckAllNotices() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.notices.ackAll()
+	uc.changed()
+}
+
+// changed 遞增狀態版本並通知訂閱者（呼叫端需持有 mu）。
+func (uc *Interactor) changed() {
+	uc.version++
+	uc.changes.notify()
+}
+
+// Subscribe 訂閱狀態變更通知；回傳的取消函式需於不再使用時呼叫。
+func (uc *Interactor) Subscribe() (<-chan struct{}, func()) { return uc.changes.subscribe() }
+
+// Version 回傳目前的狀態版本。
+func (uc *Interactor) Version() uint64 {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.expireNotices()
+	return uc.version
+}
+
+// VersionedViewModel 原子地回傳 ViewModel 與其對應的狀態版本。
+func (uc *Interactor) VersionedViewModel() (dto.ViewModelDto, uint64) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.expireNotices()
+	return uc.viewModel(), uc.version
+}
+
 func (uc *Interactor) GetViewModel() dto.ViewModelDto {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.expireNotices()
+	return uc.viewModel()
+}
+
+// expireNotices 移除過期通知；有移除時遞增版本，讓以版本判斷是否變更的讀取端（ETag、推送）
+// 不會一直沿用含過期通知的 ViewModel（呼叫端需持有 mu）。
+func (uc *Interactor) expireNotices() {
+	if uc.notices.prune(uc.clk.Now()) {
+		uc.changed()
+	}
+}
+
+// viewModel 組出 ViewModel（呼叫端需持有 mu）。
+func (uc *Interactor) viewModel() dto.ViewModelDto {
 	// 對外顯示 Knowledge/Research 以「當前語言」為主（各語言獨立累計）。
 	vm := dto.ViewModelDto{}
 	if uc.p.CurrentLanguage != "" {
@@ -67,7 +355,7 @@ func (uc *Interactor) GetViewModel() dto.ViewModelDto {
 	if uc.p.Current != nil && uc.p.Current.IsActive() {
 		now := uc.clk.Now().UTC()
 		remaining := uc.p.Current.RemainingSeconds(now)
-		endsAt := now.Add(time.Duration(remaining) * time.Second).UTC().Format(time.RFC3339)
+		endsAt := uc.p.Current.DoneAt().UTC().Format(time.RFC3339)
 		vm.CurrentTask = &dto.TaskInfo{
 			ID:               uc.p.Current.ID,
 			Type:             string(uc.p.Current.Type),
@@ -105,80 +393,74 @@ func (uc *Interactor) GetViewModel() dto.ViewModelDto {
 	vm.Servers = uc.p.Servers
 	vm.GPUs = uc.p.GPUs
 	vm.Slots = uc.p.Servers * player.SlotsPerServer
+	// 自動化設定與任務佇列
+	vm.Automation = dto.AutomationInfo{Enabled: uc.p.Automation.Enabled, AutoPractice: uc.p.Automation.AutoPractice, AutoPlay: uc.p.Automation.AutoPlay}
+	for _, q := range uc.p.Queue {
+		vm.Queue = append(vm.Queue, string(q))
+	}
+	vm.Notices = uc.notices.active(uc.clk.Now())
 	return vm
 }
 
+// 以下為各操作的便利方法，皆經由 dispatch 以同一流程執行。
+
 // StartPractice 啟動練習任務
 func (uc *Interactor) StartPractice(now time.Time) error {
-	uc.p.StartPractice(now)
-	return uc.repo.Save(uc.p, uc.ts)
+	return uc.dispatchAt(inPort.StartTask{Type: string(task.Practice)}, now)
 }
 
 // StartTargeted 啟動目標任務
 func (uc *Interactor) StartTargeted(now time.Time) error {
-	uc.p.StartTargeted(now)
-	return uc.repo.Save(uc.p, uc.ts)
+	return uc.dispatchAt(inPort.StartTask{Type: string(task.Targeted)}, now)
 }
 
 // StartDeploy 啟動部署任務
 func (uc *Interactor) StartDeploy(now time.Time) error {
-	uc.p.StartDeploy(now)
-	return uc.repo.Save(uc.p, uc.ts)
+	return uc.dispatchAt(inPort.StartTask{Type: string(task.Deploy)}, now)
 }
 
 // StartResearch 啟動研究任務
 func (uc *Interactor) StartResearch(now time.Time) error {
-	uc.p.StartResearch(now)
-	return uc.repo.Save(uc.p, uc.ts)
+	return uc.dispatchAt(inPort.StartTask{Type: string(task.Research)}, now)
 }
 
-// TryFinish 嘗試完成當前任務
+// TryFinish 推進至 now，回報此次推進中結算的任務（任務亦可能已由其他推進結算）。
 func (uc *Interactor) TryFinish(now time.Time) (finished bool, reward int64, err error) {
-	finished, reward = uc.p.TryFinish(now)
-	if err = uc.repo.Save(uc.p, uc.ts); err != nil {
-		return
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	out, err := uc.dispatch(inPort.TryFinish{}, now)
+	if err != nil {
+		return false, 0, err
 	}
-	return
+	return out.Finished, out.Reward, nil
 }
 
 // UpgradeKnowledge 升級等級，扣除研究。
-func (uc *Interactor) UpgradeKnowledge() (ok bool, err error) {
-	ok = uc.p.UpgradeKnowledge()
-	if !ok {
-		return false, nil
-	}
-	if err = uc.repo.Save(uc.p, uc.ts); err != nil {
-		return false, err
-	}
-	return true, nil
+func (uc *Interactor) UpgradeKnowledge() error {
+	return uc.dispatchAt(inPort.Upgrade{}, uc.clk.Now())
 }
 
 // SelectLanguage 設定目前操作的語言
 func (uc *Interactor) SelectLanguage(lang string) error {
-	uc.p.SelectLanguage(lang)
-	return uc.repo.Save(uc.p, uc.ts)
+	return uc.dispatchAt(inPort.SelectLanguage{Lang: lang}, uc.clk.Now())
 }
 
 // BuyServer 購買伺服器主機（佔用 Knowledge，提供顯卡插槽）
-func (uc *Interactor) BuyServer() (bool, error) {
-	ok := uc.p.BuyServer()
-	if !ok {
-		return false, nil
-	}
-	if err := uc.repo.Save(uc.p, uc.ts); err != nil {
-		return false, err
-	}
-	return true, nil
+func (uc *Interactor) BuyServer() error {
+	return uc.dispatchAt(inPort.Buy{Item: inPort.ItemServer}, uc.clk.Now())
 }
 
 // BuyGPU 購買顯卡（需有插槽，佔用 Knowledge，提升研究產率）
-func (uc *Interactor) BuyGPU() (bool, error) {
-	ok := uc.p.BuyGPU()
-	if !ok {
-		return false, nil
-	}
-	if err := uc.repo.Save(uc.p, uc.ts); err != nil {
-		return false, err
-	}
-	return true, nil
+func (uc *Interactor) BuyGPU() error {
+	return uc.dispatchAt(inPort.Buy{Item: inPort.ItemGPU}, uc.clk.Now())
+}
+
+// SetAutomation 更新此存檔的自動化設定；啟用時若閒置且允許自動 Practice，立即開始。
+func (uc *Interactor) SetAutomation(enabled, autoPractice bool) error {
+	return uc.dispatchAt(inPort.SetAutomation{Enabled: enabled, AutoPractice: autoPractice}, uc.clk.Now())
+}
+
+// EnqueueTask 將任務加入佇列；目前閒置時立即啟動。
+func (uc *Interactor) EnqueueTask(taskType string) error {
+	return uc.dispatchAt(inPort.Enqueue{Type: taskType}, uc.clk.Now())
 }
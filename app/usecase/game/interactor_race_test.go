@@ -0,0 +1,58 @@
+package game
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"go-ddd-architecture/app/domain/player"
+)
+
+// TestInteractor_ConcurrentUsecaseCalls 併發呼叫所有 Usecase 方法，搭配 `go test -race` 偵測資料競態。
+func TestInteractor_ConcurrentUsecaseCalls(t *testing.T) {
+	uc, _ := newTestInteractor(t, player.Player{
+		CurrentLanguage: "go",
+		Servers:         4,
+		Skills:          map[string]player.Skill{"go": {Knowledge: 1 << 20, Research: 1 << 20}},
+	})
+	base := time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)
+	langs := []string{"go", "py", "js"}
+
+	ops := []func(i int){
+		func(i int) { _, _ = uc.ClaimOffline(base.Add(time.Duration(i) * time.Minute)) },
+		func(i int) { _ = uc.RecordClose(base.Add(time.Duration(i) * time.Second)) },
+		func(i int) { _, _ = uc.Advance(time.Duration(i) * time.Second) },
+		func(i int) { _ = uc.GetViewModel() },
+		func(i int) { _ = uc.StartPractice(base.Add(time.Duration(i) * time.Second)) },
+		func(i int) { _ = uc.StartTargeted(base.Add(time.Duration(i) * time.Second)) },
+		func(i int) { _ = uc.StartDeploy(base.Add(time.Duration(i) * time.Second)) },
+		func(i int) { _ = uc.StartResearch(base.Add(time.Duration(i) * time.Second)) },
+		func(i int) { _, _, _ = uc.TryFinish(base.Add(time.Duration(i) * time.Minute)) },
+		func(i int) { _ = uc.UpgradeKnowledge() },
+		func(i int) { _ = uc.SelectLanguage(langs[i%len(langs)]) },
+		func(i int) { _ = uc.BuyServer() },
+		func(i int) { _ = uc.BuyGPU() },
+		func(i int) { _ = uc.SetAutomation(i%2 == 0, i%3 == 0) },
+		func(i int) { uc.AckAllNotices() },
+		func(i int) { _ = uc.EnqueueTask([]string{"Practice", "Deploy", "Research"}[i%3]) },
+		func(i int) { _ = uc.Initialize() },
+	}
+
+	const rounds = 50
+	var wg sync.WaitGroup
+	for i := 0; i < rounds; i++ {
+		for _, op := range ops {
+			wg.Add(1)
+			go func(op func(int), i int) {
+				defer wg.Done()
+				op(i)
+			}(op, i)
+		}
+	}
+	wg.Wait()
+
+	vm := uc.GetViewModel()
+	if vm.GPUs > vm.Slots {
+		t.Fatalf("invariant broken: gpus=%d slots=%d", vm.GPUs, vm.Slots)
+	}
+}
@@ -0,0 +1,307 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-ddd-architecture/app/domain/advisor"
+	"go-ddd-architecture/app/domain/gametime"
+	"go-ddd-architecture/app/domain/player"
+	"go-ddd-architecture/app/infra/memory"
+	inPort "go-ddd-architecture/app/usecase/port/in/game"
+)
+
+type fixedClock struct{ t time.Time }
+
+func (c fixedClock) Now() time.Time { return c.t }
+
+// failingRepo 可切換 Save 失敗，用於驗證快取不會被未持久化的變更污染。
+type failingRepo struct {
+	*memory.InMemoryRepo
+	fail  bool
+	saves int
+}
+
+func (r *failingRepo) Save(p player.Player, ts gametime.Timestamps) error {
+	r.saves++
+	if r.fail {
+		return errors.New("disk full")
+	}
+	return r.InMemoryRepo.Save(p, ts)
+}
+
+func newTestInteractor(t *testing.T, p player.Player) (*Interactor, *failingRepo) {
+	t.Helper()
+	repo := &failingRepo{InMemoryRepo: memory.NewInMemoryRepo()}
+	repo.P = p
+	now := time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)
+	uc := NewInteractor(repo, fixedClock{t: now}, gametime.NewOfflineCalculator())
+	if err := uc.Initialize(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	return uc, repo
+}
+
+func TestInteractor_BuyGPU_RollbackOnSaveFailure(t *testing.T) {
+	uc, repo := newTestInteractor(t, player.Player{
+		CurrentLanguage: "go",
+		Servers:         1,
+		Skills:          map[string]player.Skill{"go": {Knowledge: 500}},
+	})
+	repo.fail = true
+
+	if err := uc.BuyGPU(); err == nil {
+		t.Fatalf("expected save error")
+	}
+	vm := uc.GetViewModel()
+	if vm.GPUs != 0 || vm.Knowledge != 500 {
+		t.Fatalf("cache mutated on failed save: gpus=%d knowledge=%d", vm.GPUs, vm.Knowledge)
+	}
+
+	repo.fail = false
+	if err := uc.BuyGPU(); err != nil {
+		t.Fatalf("buy gpu: %v", err)
+	}
+	if repo.P.GPUs != 1 || uc.GetViewModel().GPUs != 1 {
+		t.Fatalf("expected committed gpu, repo=%d", repo.P.GPUs)
+	}
+}
+
+func TestInteractor_TryFinish_RollbackOnSaveFailure(t *testing.T) {
+	uc, repo := newTestInteractor(t, player.Player{CurrentLanguage: "go"})
+	start := time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)
+	if err := uc.StartPractice(start); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	repo.fail = true
+
+	finished, _, err := uc.TryFinish(start.Add(time.Minute))
+	if err == nil || finished {
+		t.Fatalf("expected failure, finished=%v err=%v", finished, err)
+	}
+	if uc.GetViewModel().CurrentTask == nil {
+		t.Fatalf("task should remain active after failed save")
+	}
+}
+
+func TestInteractor_Initialize_SettlesOfflineAndRecordClose(t *testing.T) {
+	repo := memory.NewInMemoryRepo()
+	closeAt := time.Date(2025, 8, 10, 9, 0, 0, 0, time.UTC)
+	repo.P = player.Player{CurrentLanguage: "go"}
+	repo.TS = gametime.Timestamps{WallClockAtClose: closeAt}
+	now := closeAt.Add(30 * time.Minute)
+	uc := NewInteractor(repo, fixedClock{t: now}, gametime.NewOfflineCalculator())
+
+	if err := uc.Initialize(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if res := uc.InitialOffline(); res.GainedKnowledge != 30*10 {
+		t.Fatalf("expected 30 minutes of gains, got %+v", res)
+	}
+	if !repo.TS.WallClockAtClose.Equal(now) {
+		t.Fatalf("close time not advanced: %v", repo.TS.WallClockAtClose)
+	}
+
+	later := now.Add(5 * time.Minute)
+	if err := uc.RecordClose(later); err != nil {
+		t.Fatalf("record close: %v", err)
+	}
+	if !repo.TS.WallClockAtClose.Equal(later) {
+		t.Fatalf("close time not recorded: %v", repo.TS.WallClockAtClose)
+	}
+}
+
+func TestInteractor_DomainErrorsPropagate(t *testing.T) {
+	uc, repo := newTestInteractor(t, player.Player{CurrentLanguage: "go"})
+	start := time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)
+	if err := uc.StartPractice(start); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	saved := repo.P
+
+	cases := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{"start while active", func() error { return uc.StartDeploy(start) }, player.ErrTaskAlreadyActive},
+		{"buy server", uc.BuyServer, player.ErrInsufficientKnowledge},
+		{"buy gpu without server", uc.BuyGPU, player.ErrNoFreeSlot},
+		{"upgrade", uc.UpgradeKnowledge, player.ErrInsufficientResearch},
+		{"locked language", func() error { return uc.SelectLanguage("cobol") }, player.ErrLanguageLocked},
+		{"unknown task", func() error { return uc.EnqueueTask("Nap") }, player.ErrUnknownTaskType},
+	}
+	for _, c := range cases {
+		if err := c.call(); !errors.Is(err, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.want)
+		}
+	}
+	if repo.P.Current == nil || repo.P.Current.Type != saved.Current.Type {
+		t.Fatalf("rejected commands must not be saved")
+	}
+}
+
+func TestInteractor_Dispatch_AppliesAndAudits(t *testing.T) {
+	uc, repo := newTestInteractor(t, player.Player{
+		CurrentLanguage: "go",
+		Skills:          map[string]player.Skill{"py": {Knowledge: 500, Research: 100}},
+	})
+
+	cmd, err := inPort.DecodeCommand(inPort.CmdUpgrade, []byte(`{"lang":"py"}`))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if _, err := uc.Dispatch(cmd); err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	if _, err := uc.Dispatch(inPort.Buy{Item: "yacht"}); !errors.Is(err, inPort.ErrInvalidCommand) {
+		t.Fatalf("expected ErrInvalidCommand, got %v", err)
+	}
+	if _, err := inPort.DecodeCommand("launch", nil); !errors.Is(err, inPort.ErrInvalidCommand) {
+		t.Fatalf("expected unknown command error, got %v", err)
+	}
+
+	if s := repo.P.Skills["py"]; repo.P.CurrentLanguage != "py" || s.Level != 1 {
+		t.Fatalf("upgrade not persisted: lang=%s skill=%+v", repo.P.CurrentLanguage, s)
+	}
+	audit := uc.Audit()
+	if len(audit) != 2 || audit[0].OK || audit[1].Command != inPort.CmdUpgrade || !audit[1].OK {
+		t.Fatalf("unexpected audit trail: %+v", audit)
+	}
+}
+
+func TestInteractor_DispatchBatch_AllOrNothing(t *testing.T) {
+	uc, repo := newTestInteractor(t, player.Player{
+		CurrentLanguage: "go",
+		Skills:          map[string]player.Skill{"py": {Research: 250}},
+	})
+	saves := repo.saves
+
+	// 第三步研究不足：整批不生效、不存檔
+	_, err := uc.DispatchBatch([]inPort.Command{
+		inPort.SelectLanguage{Lang: "py"},
+		inPort.Upgrade{},
+		inPort.Upgrade{},
+		inPort.StartTask{Type: "Deploy"},
+	})
+	var be *inPort.BatchError
+	if !errors.As(err, &be) || be.Index != 2 || !errors.Is(err, player.ErrInsufficientResearch) {
+		t.Fatalf("expected failure at step 2, got %v", err)
+	}
+	if repo.saves != saves || uc.GetViewModel().CurrentLanguage != "go" {
+		t.Fatalf("failed batch must not change state (saves %d -> %d)", saves, repo.saves)
+	}
+
+	res, err := uc.DispatchBatch([]inPort.Command{
+		inPort.SelectLanguage{Lang: "py"},
+		inPort.Upgrade{},
+		inPort.StartTask{Type: "Deploy"},
+	})
+	if err != nil || !res.Applied || len(res.Steps) != 3 {
+		t.Fatalf("batch: %+v err=%v", res, err)
+	}
+	if repo.saves != saves+1 {
+		t.Fatalf("expected exactly one save, got %d", repo.saves-saves)
+	}
+	vm := uc.GetViewModel()
+	if vm.CurrentLanguage != "py" || vm.Level != 1 || vm.CurrentTask == nil || vm.CurrentTask.Type != "Deploy" {
+		t.Fatalf("unexpected state after batch: %+v", vm)
+	}
+}
+
+func TestInteractor_AdvanceTo_AutoPlayFollowsAdvisor(t *testing.T) {
+	uc, repo := newTestInteractor(t, player.Player{
+		CurrentLanguage: "go",
+		Servers:         1,
+		Automation:      player.Automation{Enabled: true, AutoPractice: true, AutoPlay: true},
+		Skills:          map[string]player.Skill{"go": {Knowledge: 90}},
+	})
+	now := time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)
+	if _, err := uc.AdvanceTo(now.Add(time.Second)); err != nil {
+		t.Fatalf("advance: %v", err)
+	}
+	if repo.P.GPUs != 1 {
+		t.Fatalf("auto-play should buy the affordable GPU, got %+v", repo.P)
+	}
+	if a := uc.Audit(); len(a) == 0 || a[0].Command != inPort.CmdBuy {
+		t.Fatalf("auto-play action not audited: %+v", a)
+	}
+	if adv := uc.Advice(); len(adv.Recommendations) == 0 || adv.Recommendations[0].Command == "" {
+		t.Fatalf("advice should carry dispatchable commands: %+v", adv)
+	}
+}
+
+// 未知的顧問動作不可被當成其他命令執行（例如退回升級）。
+func TestActionCommand_UnknownKind(t *testing.T) {
+	if cmd, err := actionCommand(advisor.Action{Kind: "teleport"}); !errors.Is(err, advisor.ErrUnknownAction) || cmd != nil {
+		t.Fatalf("unknown action should be rejected: %v %v", cmd, err)
+	}
+	if cmd, err := actionCommand(advisor.Action{Kind: advisor.Upgrade}); err != nil || cmd != (inPort.Upgrade{}) {
+		t.Fatalf("upgrade: %v %v", cmd, err)
+	}
+}
+
+type recordingObserver struct{ started, finished []string }
+
+func (o *recordingObserver) TaskStarted(tt string) { o.started = append(o.started, tt) }
+func (o *recordingObserver) TaskFinished(tt string, success bool) {
+	o.finished = append(o.finished, fmt.Sprintf("%s:%v", tt, success))
+}
+
+func TestInteractor_TaskObserver_StartsAndCompletions(t *testing.T) {
+	uc, _ := newTestInteractor(t, player.Player{CurrentLanguage: "go"})
+	obs := &recordingObserver{}
+	uc.SetTaskObserver(obs)
+	now := time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)
+	if err := uc.StartPractice(now); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := uc.EnqueueTask("Practice"); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if _, err := uc.AdvanceTo(now.Add(2 * time.Hour)); err != nil {
+		t.Fatalf("advance: %v", err)
+	}
+	// 第一個任務開始一次；結算時由佇列接續的任務於推進中開始並結算
+	if len(obs.started) != 2 || len(obs.finished) != 2 || obs.started[1] != "Practice" {
+		t.Fatalf("unexpected events: started=%v finished=%v", obs.started, obs.finished)
+	}
+}
+
+func TestInteractor_Initialize_FirstRunUsesInjectedClock(t *testing.T) {
+	// 開發時鐘偏移 10 小時：首次啟動不可因儲存層以系統時間補關閉時間而獲得離線收益
+	now := time.Now().Add(10 * time.Hour)
+	repo := memory.NewInMemoryRepo()
+	uc := NewInteractor(repo, fixedClock{t: now}, gametime.NewOfflineCalculator())
+	if err := uc.Initialize(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if res := uc.InitialOffline(); res.GainedKnowledge != 0 || res.AnomalyDetected {
+		t.Fatalf("first run should not settle offline time: %+v", res)
+	}
+	if !repo.TS.WallClockAtClose.Equal(now) {
+		t.Fatalf("close time should come from the clock port: %v", repo.TS.WallClockAtClose)
+	}
+}
+
+func TestInteractor_DispatchBatch_TryFinishAfterOtherStep(t *testing.T) {
+	uc, _ := newTestInteractor(t, player.Player{CurrentLanguage: "go"})
+	now := time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)
+	if err := uc.StartPractice(now.Add(-time.Minute)); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	res, err := uc.DispatchBatch([]inPort.Command{
+		inPort.SelectLanguage{Lang: "go"},
+		inPort.TryFinish{},
+		inPort.TryFinish{},
+	})
+	if err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	// 推進結果歸屬第一個 try-finish，而非第一個步驟；之後的 try-finish 不重複計算
+	if !res.Steps[1].Result.Finished || res.Steps[2].Result.Finished {
+		t.Fatalf("advance result should go to the first try-finish: %+v", res.Steps)
+	}
+}
@@ -0,0 +1,127 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	dto "go-ddd-architecture/app/usecase/dto/game"
+)
+
+// Severity 為通知的嚴重程度。
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// 各嚴重程度的預設存活時間；error 不過期，需確認後才移除。
+var noticeTTL = map[Severity]time.Duration{
+	SeverityInfo:    2 * time.Minute,
+	SeverityWarning: 10 * time.Minute,
+	SeverityError:   0,
+}
+
+// maxNotices 為保留的未確認通知上限，超過時捨棄最舊者。
+const maxNotices = 20
+
+type notice struct {
+	id        string
+	key       string
+	severity  Severity
+	message   string
+	count     int
+	at        time.Time
+	expiresAt time.Time // 零值表示不過期
+}
+
+// noticeCenter 收集需要提示玩家的事件（離線結算、時間異常、任務失敗、升級、錯誤），
+// 於 ViewModel 中持續提供直到被確認或過期。同一 key 的未確認通知合併為一則並累計次數。
+// 僅存在於記憶體；由 Interactor 的 mu 保護。
+type noticeCenter struct {
+	seq   uint64
+	items []*notice
+}
+
+// add 新增或合併通知；回傳通知 ID。
+func (c *noticeCenter) add(key string, sev Severity, msg string, now time.Time) string {
+	c.prune(now)
+	var exp time.Time
+	if ttl := noticeTTL[sev]; ttl > 0 {
+		exp = now.Add(ttl)
+	}
+	for _, n := range c.items {
+		if n.key == key {
+			n.severity, n.message, n.at, n.expiresAt = sev, msg, now, exp
+			n.count++
+			return n.id
+		}
+	}
+	c.seq++
+	n := &notice{id: fmt.Sprintf("n-%d", c.seq), key: key, severity: sev, message: msg, count: 1, at: now, expiresAt: exp}
+	c.items = append(c.items, n)
+	if len(c.items) > maxNotices {
+		c.items = c.items[len(c.items)-maxNotices:]
+	}
+	return n.id
+}
+
+// listed 判斷指定 key 的通知是否仍在列表中（呼叫端需先 prune 以排除過期者）。
+func (c *noticeCenter) listed(key string) bool {
+	for _, n := range c.items {
+		if n.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ack 確認（移除）指定通知；不存在時回傳 false。
+func (c *noticeCenter) ack(id string) bool {
+	for i, n := range c.items {
+		if n.id == id {
+			c.items = append(c.items[:i], c.items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (c *noticeCenter) ackAll() { c.items = nil }
+
+// prune 移除已過期的通知，回傳是否有移除。
+func (c *noticeCenter) prune(now time.Time) bool {
+	kept := c.items[:0]
+	for _, n := range c.items {
+		if n.expiresAt.IsZero() || now.Before(n.expiresAt) {
+			kept = append(kept, n)
+		}
+	}
+	removed := len(kept) != len(c.items)
+	c.items = kept
+	return removed
+}
+
+// active 回傳未過期的通知（最新在前）。
+func (c *noticeCenter) active(now time.Time) []dto.NoticeDto {
+	c.prune(now)
+	out := make([]dto.NoticeDto, 0, len(c.items))
+	for i := len(c.items) - 1; i >= 0; i-- {
+		n := c.items[i]
+		d := dto.NoticeDto{
+			ID:       n.id,
+			Severity: string(n.severity),
+			Message:  n.message,
+			Count:    n.count,
+			At:       n.at.UTC().Format(time.RFC3339),
+		}
+		if !n.expiresAt.IsZero() {
+			d.ExpiresAt = n.expiresAt.UTC().Format(time.RFC3339)
+		}
+		out = append(out, d)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].At > out[j].At })
+	return out
+}
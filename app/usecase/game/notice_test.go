@@ -0,0 +1,103 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-ddd-architecture/app/domain/player"
+	inPort "go-ddd-architecture/app/usecase/port/in/game"
+)
+
+func TestNoticeCenter_DedupeExpiryAck(t *testing.T) {
+	var c noticeCenter
+	now := time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)
+	id := c.add("task-failed:Deploy", SeverityWarning, "Deploy task failed", now)
+	if again := c.add("task-failed:Deploy", SeverityWarning, "Deploy task failed", now.Add(time.Second)); again != id {
+		t.Fatalf("expected dedupe into %s, got %s", id, again)
+	}
+	errID := c.add("save-failed", SeverityError, "save failed", now)
+
+	got := c.active(now.Add(time.Second))
+	if len(got) != 2 || got[0].ID != id || got[0].Count != 2 {
+		t.Fatalf("unexpected notices: %+v", got)
+	}
+	// warning 過期後消失；error 不過期
+	got = c.active(now.Add(time.Hour))
+	if len(got) != 1 || got[0].ID != errID || got[0].ExpiresAt != "" {
+		t.Fatalf("expected only the error notice, got %+v", got)
+	}
+	if !c.ack(errID) || c.ack(errID) {
+		t.Fatalf("ack should succeed once")
+	}
+	if len(c.active(now.Add(time.Hour))) != 0 {
+		t.Fatalf("expected no notices after ack")
+	}
+}
+
+func TestInteractor_NoticesFromRejectionsAndUpgrades(t *testing.T) {
+	uc, _ := newTestInteractor(t, player.Player{
+		CurrentLanguage: "go",
+		Skills:          map[string]player.Skill{"go": {Research: 1000}},
+	})
+	uc.AckAllNotices() // 忽略初始化時的離線結算通知
+	if err := uc.BuyServer(); err == nil {
+		t.Fatalf("expected rejection")
+	}
+	if err := uc.UpgradeKnowledge(); err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	vm := uc.GetViewModel()
+	if len(vm.Notices) != 2 || vm.Notices[0].Message != "go reached Lv 1" || vm.Notices[1].Severity != string(SeverityWarning) {
+		t.Fatalf("unexpected notices: %+v", vm.Notices)
+	}
+
+	if err := uc.AckNotice(vm.Notices[0].ID); err != nil {
+		t.Fatalf("ack: %v", err)
+	}
+	if err := uc.AckNotice(vm.Notices[0].ID); !errors.Is(err, inPort.ErrNoticeNotFound) {
+		t.Fatalf("expected ErrNoticeNotFound, got %v", err)
+	}
+	uc.AckAllNotices()
+	if n := len(uc.GetViewModel().Notices); n != 0 {
+		t.Fatalf("expected notices cleared, got %d", n)
+	}
+}
+
+// 通知過期會改變 ViewModel，版本也需遞增，否則以版本判斷的 ETag 會一直沿用過期通知。
+func TestInteractor_NoticeExpiryBumpsVersion(t *testing.T) {
+	uc, _ := newTestInteractor(t, player.Player{CurrentLanguage: "go"})
+	uc.AckAllNotices()
+	if err := uc.BuyServer(); err == nil {
+		t.Fatalf("expected rejection")
+	}
+	v := uc.Version()
+	if uc.Version() != v {
+		t.Fatalf("version should be stable while nothing changes")
+	}
+	uc.clk = fixedClock{t: uc.clk.Now().Add(time.Hour)}
+	if uc.Version() == v {
+		t.Fatalf("version should change when the notice expires")
+	}
+	if n := len(uc.GetViewModel().Notices); n != 0 {
+		t.Fatalf("expected expired notice gone, got %d", n)
+	}
+}
+
+// 被拒絕的命令不改變狀態：版本不變，重複的拒絕也不重複記錄。
+func TestInteractor_RejectionsKeepVersion(t *testing.T) {
+	uc, _ := newTestInteractor(t, player.Player{CurrentLanguage: "go"})
+	uc.AckAllNotices()
+	v := uc.Version()
+	for i := 0; i < 3; i++ {
+		if err := uc.BuyServer(); err == nil {
+			t.Fatalf("expected rejection")
+		}
+	}
+	if uc.Version() != v {
+		t.Fatalf("rejections should not bump the version: %d -> %d", v, uc.Version())
+	}
+	if n := uc.GetViewModel().Notices; len(n) != 1 || n[0].Count != 1 {
+		t.Fatalf("repeated rejection should be listed once: %+v", n)
+	}
+}
@@ -0,0 +1,39 @@
+package game
+
+import "sync"
+
+// changeNotifier 在狀態提交後通知訂閱者（排程器、推播等）。
+// 每個訂閱者有容量 1 的 channel：多次變更合併為一次通知，不會阻塞提交。
+type changeNotifier struct {
+	mu   sync.Mutex
+	next int
+	subs map[int]chan struct{}
+}
+
+func (n *changeNotifier) subscribe() (<-chan struct{}, func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.subs == nil {
+		n.subs = map[int]chan struct{}{}
+	}
+	id := n.next
+	n.next++
+	ch := make(chan struct{}, 1)
+	n.subs[id] = ch
+	return ch, func() {
+		n.mu.Lock()
+		delete(n.subs, id)
+		n.mu.Unlock()
+	}
+}
+
+func (n *changeNotifier) notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, ch := range n.subs {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package game
+
+import (
+	"context"
+	"time"
+)
+
+// Scheduler 在目前任務的完成時間喚醒並推進狀態，使自動化在沒有客戶端時也能持續進行。
+// 狀態變更（例如新任務開始、自動化設定調整）時重新計算下一次喚醒時間。
+type Scheduler struct {
+	uc      *Interactor
+	clk     Clock
+	backoff time.Duration
+}
+
+func NewScheduler(uc *Interactor, clk Clock) *Scheduler {
+	return &Scheduler{uc: uc, clk: clk, backoff: time.Second}
+}
+
+// Run 執行排程直到 ctx 結束；推進失敗時呼叫 onErr 並稍候重試。
+func (s *Scheduler) Run(ctx context.Context, onErr func(error)) {
+	changes, cancel := s.uc.Subscribe()
+	defer cancel()
+	for {
+		var (
+			timer  *time.Timer
+			timerC <-chan time.Time
+		)
+		if at, ok := s.uc.NextWake(); ok {
+			timer = time.NewTimer(s.realWait(at.Sub(s.clk.Now())))
+			timerC = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			stopTimer(timer)
+			return
+		case <-changes:
+			stopTimer(timer)
+		case <-timerC:
+			if _, err := s.uc.AdvanceTo(s.clk.Now()); err != nil {
+				if onErr != nil {
+					onErr(err)
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(s.backoff):
+				}
+			}
+		}
+	}
+}
+
+// scaledClock 為選用能力：時鐘以倍率流逝時（開發模式加速），遊戲時間 d 對應實際等待 d/倍率。
+type scaledClock interface{ Scale() float64 }
+
+func (s *Scheduler) realWait(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	if sc, ok := s.clk.(scaledClock); ok {
+		if scale := sc.Scale(); scale > 0 {
+			return time.Duration(float64(d) / scale)
+		}
+	}
+	return d
+}
+
+func stopTimer(t *time.Timer) {
+	if t != nil {
+		t.Stop()
+	}
+}
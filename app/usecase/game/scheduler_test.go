@@ -0,0 +1,72 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-ddd-architecture/app/domain/gametime"
+	"go-ddd-architecture/app/domain/player"
+	"go-ddd-architecture/app/infra/memory"
+)
+
+type wallClock struct{}
+
+func (wallClock) Now() time.Time { return time.Now() }
+
+func TestScheduler_ResolvesTaskWithoutClient(t *testing.T) {
+	repo := memory.NewInMemoryRepo()
+	repo.P = player.Player{CurrentLanguage: "go", Automation: player.Automation{Enabled: true}}
+	uc := NewInteractor(repo, wallClock{}, gametime.NewOfflineCalculator())
+	if err := uc.Initialize(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	// 任務開始於過去，完成時間已到：排程器應立即結算
+	if err := uc.StartPractice(time.Now().Add(-10 * time.Second)); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if uc.GetViewModel().CurrentTask == nil {
+		t.Fatalf("expected active task before scheduler runs")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		NewScheduler(uc, wallClock{}).Run(ctx, func(err error) { t.Errorf("advance: %v", err) })
+	}()
+	defer func() { cancel(); <-done }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if uc.GetViewModel().CurrentTask == nil {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("scheduler did not resolve the task")
+}
+
+func TestScheduler_NoSpinWhenClockBehindLastSeen(t *testing.T) {
+	uc, repo := newTestInteractor(t, player.Player{CurrentLanguage: "go", Automation: player.Automation{Enabled: true}})
+	now := time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)
+	// 推進後 LastSeen 領先時鐘一小時（如開發時鐘倒退），之後以時鐘時間開始的任務完成時間早於 LastSeen
+	if _, err := uc.Advance(time.Hour); err != nil {
+		t.Fatalf("advance: %v", err)
+	}
+	if err := uc.StartPractice(now.Add(-10 * time.Second)); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	saves := repo.saves
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	NewScheduler(uc, fixedClock{t: now}).Run(ctx, func(err error) { t.Errorf("advance: %v", err) })
+
+	if n := repo.saves - saves; n > 1 {
+		t.Fatalf("scheduler spun without progress: %d saves", n)
+	}
+	if uc.GetViewModel().CurrentTask != nil {
+		t.Fatalf("task due by the clock should settle even though LastSeen is ahead")
+	}
+}
@@ -0,0 +1,120 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCommand 命令名稱未知或參數不合法。
+var ErrInvalidCommand = errors.New("invalid command")
+
+// MaxBatch 為單一批次的命令數上限。
+const MaxBatch = 50
+
+// BatchError 表示批次在第 Index 個步驟失敗（整批未生效）；Unwrap 回傳該步驟的錯誤。
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string { return fmt.Sprintf("batch step %d: %v", e.Index, e.Err) }
+func (e *BatchError) Unwrap() error { return e.Err }
+
+// 穩定的命令名稱（/commands 端點、稽核紀錄與事件日誌使用）。
+const (
+	CmdStartTask      = "start-task"
+	CmdEnqueue        = "enqueue"
+	CmdUpgrade        = "upgrade"
+	CmdBuy            = "buy"
+	CmdSelectLanguage = "select-language"
+	CmdSetAutomation  = "set-automation"
+	CmdTryFinish      = "try-finish"
+)
+
+// 可購買的商品（Buy.Item）。
+const (
+	ItemServer = "server"
+	ItemGPU    = "gpu"
+)
+
+// Command 為可交由 Usecase.Dispatch 執行的玩家操作。
+// 所有命令皆以同一流程處理：推進至現在 → 驗證並套用 → 持久化 → 稽核。
+type Command interface {
+	CommandName() string
+}
+
+// StartTask 開始任務；Lang 非空時先切換至該語言。
+type StartTask struct {
+	Type string `json:"type"`
+	Lang string `json:"lang,omitempty"`
+}
+
+// Enqueue 將任務加入佇列；閒置時立即啟動。
+type Enqueue struct {
+	Type string `json:"type"`
+}
+
+// Upgrade 升級語言等級；Lang 非空時先切換至該語言。
+type Upgrade struct {
+	Lang string `json:"lang,omitempty"`
+}
+
+// Buy 購買商品（ItemServer / ItemGPU）。
+type Buy struct {
+	Item string `json:"item"`
+}
+
+// SelectLanguage 切換目前語言。
+type SelectLanguage struct {
+	Lang string `json:"lang"`
+}
+
+// SetAutomation 設定伺服器端自動化；AutoPlay 為 nil 時維持原設定。
+type SetAutomation struct {
+	Enabled      bool  `json:"enabled"`
+	AutoPractice bool  `json:"autoPractice"`
+	AutoPlay     *bool `json:"autoPlay,omitempty"`
+}
+
+// TryFinish 推進至現在並回報此次結算的任務獎勵。
+type TryFinish struct{}
+
+func (StartTask) CommandName() string      { return CmdStartTask }
+func (Enqueue) CommandName() string        { return CmdEnqueue }
+func (Upgrade) CommandName() string        { return CmdUpgrade }
+func (Buy) CommandName() string            { return CmdBuy }
+func (SelectLanguage) CommandName() string { return CmdSelectLanguage }
+func (SetAutomation) CommandName() string  { return CmdSetAutomation }
+func (TryFinish) CommandName() string      { return CmdTryFinish }
+
+// DecodeCommand 依名稱將 JSON 參數解碼為對應命令；名稱未知或參數格式錯誤時回傳 ErrInvalidCommand。
+func DecodeCommand(name string, args json.RawMessage) (Command, error) {
+	switch name {
+	case CmdStartTask:
+		return decodeArgs[StartTask](args)
+	case CmdEnqueue:
+		return decodeArgs[Enqueue](args)
+	case CmdUpgrade:
+		return decodeArgs[Upgrade](args)
+	case CmdBuy:
+		return decodeArgs[Buy](args)
+	case CmdSelectLanguage:
+		return decodeArgs[SelectLanguage](args)
+	case CmdSetAutomation:
+		return decodeArgs[SetAutomation](args)
+	case CmdTryFinish:
+		return decodeArgs[TryFinish](args)
+	}
+	return nil, fmt.Errorf("%w: unknown command %q", ErrInvalidCommand, name)
+}
+
+func decodeArgs[T Command](args json.RawMessage) (Command, error) {
+	var c T
+	if len(args) > 0 && string(args) != "null" {
+		if err := json.Unmarshal(args, &c); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidCommand, c.CommandName(), err)
+		}
+	}
+	return c, nil
+}
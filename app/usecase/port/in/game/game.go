@@ -1,24 +1,60 @@
 package game
 
 import (
+	"errors"
 	"time"
 
 	"go-ddd-architecture/app/domain/gametime"
+	"go-ddd-architecture/app/domain/player"
 	dto "go-ddd-architecture/app/usecase/dto/game"
 )
 
 // Usecase 定義 Game 的 Input Port。
+// 違反遊戲規則時回傳 player 套件的領域錯誤（如 player.ErrTaskAlreadyActive），可用 errors.Is 判斷。
 type Usecase interface {
+	// Initialize 載入存檔並自動結算離線收益。
 	Initialize() error
 	ClaimOffline(now time.Time) (gametime.OfflineResult, error)
+	// RecordClose 記錄關閉時間（心跳/關機），供下次啟動的離線結算使用。
+	RecordClose(now time.Time) error
+	// Advance 將所有時間相關狀態推進 dt（任務結算、被動產出）；其餘變更操作皆先推進至當下。
+	Advance(dt time.Duration) (player.AdvanceResult, error)
+	// AdvanceTo 將狀態推進到指定時間點（排程器與開發模式的時鐘調整使用）。
+	AdvanceTo(now time.Time) (player.AdvanceResult, error)
 	GetViewModel() dto.ViewModelDto
+	// Version 回傳目前的狀態版本（條件式 GET 用來判斷是否需要重新產生 ViewModel）。
+	Version() uint64
+	// VersionedViewModel 原子地回傳 ViewModel 與狀態版本（每次狀態或通知變更時遞增）。
+	VersionedViewModel() (dto.ViewModelDto, uint64)
+	// Subscribe 訂閱狀態變更通知（多次變更可能合併為一次）；回傳的取消函式需於不再使用時呼叫。
+	Subscribe() (<-chan struct{}, func())
 	StartPractice(now time.Time) error
 	StartTargeted(now time.Time) error
 	StartDeploy(now time.Time) error
 	StartResearch(now time.Time) error
 	TryFinish(now time.Time) (finished bool, reward int64, err error)
-	UpgradeKnowledge() (ok bool, err error)
+	UpgradeKnowledge() error
 	SelectLanguage(lang string) error
-	BuyServer() (bool, error)
-	BuyGPU() (bool, error)
+	BuyServer() error
+	BuyGPU() error
+	// SetAutomation 設定伺服器端自動結算與自動 Practice（每個存檔各自保存）。
+	SetAutomation(enabled, autoPractice bool) error
+	// EnqueueTask 將任務類型加入佇列；佇列已滿或類型未知時回傳對應的領域錯誤。
+	EnqueueTask(taskType string) error
+	// AckNotice 確認（移除）ViewModel 中的通知；ID 不存在時回傳 ErrNoticeNotFound。
+	AckNotice(id string) error
+	AckAllNotices()
+	// Dispatch 執行型別化命令（上方各操作方法皆為其便利包裝）；命令不合法時回傳 ErrInvalidCommand。
+	Dispatch(cmd Command) (dto.CommandResultDto, error)
+	// DispatchBatch 依序套用多個命令，全部成功才以單次存檔生效；任一步驟失敗時回傳 *BatchError 且狀態不變。
+	DispatchBatch(cmds []Command) (dto.BatchResultDto, error)
+	// Audit 回傳最近的命令稽核紀錄（最新在前）。
+	Audit() []dto.AuditEntryDto
+	// Explain 拆解各任務類型的成功率、時長與獎勵；lang 非空時以該語言計算（不切換目前語言）。
+	Explain(lang string) (dto.ExplainDto, error)
+	// Advice 回傳進度預測與依 ROI 排序的建議。
+	Advice() dto.AdviceDto
 }
+
+// ErrNoticeNotFound 通知不存在（已確認或已過期）。
+var ErrNoticeNotFound = errors.New("notice not found")
@@ -0,0 +1,15 @@
+package game
+
+import (
+	"time"
+
+	dto "go-ddd-architecture/app/usecase/dto/game"
+)
+
+// HistoryUsecase 定義統計時間序列的 Input Port。
+type HistoryUsecase interface {
+	// Sample 以目前狀態取樣一次並寫入時間序列。
+	Sample() error
+	// History 依粒度（minute/hour/day）回傳 [from, to] 的時間序列；from 為零值時使用預設時間窗。
+	History(res string, from, to time.Time) (dto.HistoryDto, error)
+}
@@ -0,0 +1,9 @@
+package game
+
+import "time"
+
+// Clock 為時間來源 Port：用例與 HTTP adapter 的所有時間讀取都經由此介面，
+// 以便在測試或開發模式下替換為可控制的時鐘（偏移、加速）。
+type Clock interface {
+	Now() time.Time
+}
@@ -0,0 +1,14 @@
+package game
+
+import (
+	"time"
+
+	"go-ddd-architecture/app/domain/stats"
+)
+
+// HistoryStore 定義統計時間序列的持久化 Port。
+// Record 需同時更新各粒度的 rollup 桶；Query 依粒度回傳 [from, to] 內依時間排序的取樣。
+type HistoryStore interface {
+	Record(s stats.Sample) error
+	Query(res stats.Resolution, from, to time.Time) ([]stats.Sample, error)
+}
@@ -0,0 +1,10 @@
+package game
+
+// TaskObserver 為選用的觀測 Port：用例在狀態提交成功後回報任務的開始與結算（例如匯出 metrics）。
+// 由用例的 mu 保護呼叫順序；實作不可阻塞，也不可回呼用例。
+type TaskObserver interface {
+	// TaskStarted 回報開始的任務（含推進時由佇列或自動 Practice 接續者）。
+	TaskStarted(taskType string)
+	// TaskFinished 回報結算的任務與結果。
+	TaskFinished(taskType string, success bool)
+}
@@ -10,3 +10,15 @@ type Repository interface {
 	Load() (player.Player, gametime.Timestamps, error)
 	Save(player.Player, gametime.Timestamps) error
 }
+
+// Command 描述一次造成狀態變更的用例操作（名稱與選用參數）。
+type Command struct {
+	Name string `json:"name"`
+	Arg  string `json:"arg,omitempty"`
+}
+
+// CommandLogger 為 Repository 的選用能力：除了狀態本身，也記錄是哪個命令造成變更。
+// 事件日誌型實作（append-only）可藉此保留完整歷史；未實作者由用例退回 Save。
+type CommandLogger interface {
+	SaveCommand(cmd Command, p player.Player, ts gametime.Timestamps) error
+}
@@ -0,0 +1,56 @@
+package simulate
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// WriteCSV 以 CSV 輸出時間序列（每個樣本一列）；各語言等級為 level_<lang> 欄位。
+func WriteCSV(w io.Writer, r Result) error {
+	langs := r.Languages()
+	header := []string{"minute", "at", "language", "knowledge", "research", "knowledge_per_min", "research_per_min",
+		"servers", "gpus", "tasks_ok", "tasks_failed", "upgrades", "purchases", "switches"}
+	for _, lang := range langs {
+		header = append(header, "level_"+lang)
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, s := range r.Samples {
+		row := []string{
+			strconv.FormatFloat(s.Minute, 'f', -1, 64),
+			s.At.UTC().Format(time.RFC3339),
+			s.Language,
+			strconv.FormatInt(s.Knowledge, 10),
+			strconv.FormatInt(s.Research, 10),
+			strconv.FormatInt(s.KnowledgePerMin, 10),
+			strconv.FormatInt(s.ResearchPerMin, 10),
+			strconv.Itoa(s.Servers),
+			strconv.Itoa(s.GPUs),
+			strconv.Itoa(s.TasksOK),
+			strconv.Itoa(s.TasksFailed),
+			strconv.Itoa(s.Upgrades),
+			strconv.Itoa(s.Purchases),
+			strconv.Itoa(s.Switches),
+		}
+		for _, lang := range langs {
+			row = append(row, strconv.Itoa(s.Levels[lang]))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON 以 JSON 輸出樣本與事件（購買、升級、離線結算）。
+func WriteJSON(w io.Writer, r Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
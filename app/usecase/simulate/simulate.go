@@ -0,0 +1,270 @@
+// Package simulate 以假時鐘驅動真實的領域模型（player.Player、gametime.OfflineCalculator），
+// 依可替換的遊玩策略快速模擬數小時的進度，用於檢查平衡調整是否造成節奏退化。
+package simulate
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"go-ddd-architecture/app/domain/advisor"
+	"go-ddd-architecture/app/domain/gametime"
+	"go-ddd-architecture/app/domain/player"
+	"go-ddd-architecture/app/domain/task"
+)
+
+// maxActionsPerStep 限制每一步策略可連續執行的動作數（與伺服器 auto-play 相同的保護）。
+const maxActionsPerStep = 10
+
+// Config 為一次模擬的設定；零值欄位使用預設值。
+type Config struct {
+	// Player 為初始狀態（零值即新存檔）
+	Player player.Player
+	// Epoch 為模擬起始時間；任務成功判定以完成時間為種子，同設定的結果可重現
+	Epoch    time.Time
+	Duration time.Duration
+	// Step 為線上推進的步長（預設 5s），策略於每一步後行動
+	Step time.Duration
+	// SampleEvery 為取樣間隔（預設 10m）
+	SampleEvery time.Duration
+	Policy      advisor.Policy
+	// Task 為持續執行的任務類型（預設 Practice），以佇列補滿的方式讓任務不中斷
+	Task task.Type
+	// OfflineEvery>0 時，每線上遊玩 OfflineEvery 就關閉遊戲 OfflineFor，並以 OfflineCalculator 結算
+	OfflineEvery time.Duration
+	OfflineFor   time.Duration
+}
+
+// Sample 為某時間點的資源、等級與累計購買。
+type Sample struct {
+	Minute          float64
+	At              time.Time
+	Language        string
+	Knowledge       int64
+	Research        int64
+	Levels          map[string]int
+	KnowledgePerMin int64
+	ResearchPerMin  int64
+	Servers         int
+	GPUs            int
+	TasksOK         int
+	TasksFailed     int
+	Upgrades        int
+	Purchases       int
+	Switches        int
+}
+
+// Event 為策略動作或離線結算。
+type Event struct {
+	Minute float64
+	At     time.Time
+	// upgrade | buy-server | buy-gpu | switch-language | offline
+	Kind   string
+	Lang   string `json:",omitempty"`
+	Detail string `json:",omitempty"`
+}
+
+// Result 為模擬結果。
+type Result struct {
+	Samples []Sample
+	Events  []Event
+	Final   player.Player `json:"-"`
+}
+
+// fakeClock 為模擬用的時鐘：只在模擬推進時前進。
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+var _ gametime.Clock = (*fakeClock)(nil)
+
+// Run 執行模擬。
+func Run(cfg Config) (Result, error) {
+	if cfg.Duration <= 0 {
+		return Result{}, errors.New("simulate: duration must be positive")
+	}
+	if cfg.Policy == nil {
+		return Result{}, errors.New("simulate: policy is required")
+	}
+	if cfg.OfflineEvery > 0 && cfg.OfflineFor <= 0 {
+		return Result{}, errors.New("simulate: offline-for must be positive when offline-every is set")
+	}
+	if cfg.Step <= 0 {
+		cfg.Step = 5 * time.Second
+	}
+	if cfg.SampleEvery <= 0 {
+		cfg.SampleEvery = 10 * time.Minute
+	}
+	if cfg.Task == "" {
+		cfg.Task = task.Practice
+	}
+	if !cfg.Task.Valid() {
+		return Result{}, player.ErrUnknownTaskType
+	}
+	if cfg.Epoch.IsZero() {
+		cfg.Epoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	s := &sim{cfg: cfg, clk: &fakeClock{now: cfg.Epoch}, calc: gametime.NewOfflineCalculator(), p: cfg.Player.Clone()}
+	if s.p.CurrentLanguage == "" {
+		s.p.CurrentLanguage = "go"
+	}
+	if err := s.p.SelectLanguage(s.p.CurrentLanguage); err != nil {
+		return Result{}, err
+	}
+	s.p.LastSeen = cfg.Epoch
+	s.run()
+	return Result{Samples: s.samples, Events: s.events, Final: s.p}, nil
+}
+
+type sim struct {
+	cfg  Config
+	clk  *fakeClock
+	calc *gametime.OfflineCalculator
+	p    player.Player
+
+	samples []Sample
+	events  []Event
+	ok      int
+	failed  int
+	counts  map[advisor.Kind]int
+}
+
+func (s *sim) run() {
+	s.counts = map[advisor.Kind]int{}
+	end := s.cfg.Epoch.Add(s.cfg.Duration)
+	nextSample := s.cfg.Epoch
+	var online time.Duration
+	for {
+		now := s.clk.Now()
+		if !now.Before(nextSample) {
+			s.sample(now)
+			for !nextSample.After(now) {
+				nextSample = nextSample.Add(s.cfg.SampleEvery)
+			}
+		}
+		if !now.Before(end) {
+			break
+		}
+		s.act(now)
+		s.keepBusy(now)
+
+		if s.cfg.OfflineEvery > 0 && online >= s.cfg.OfflineEvery {
+			s.offline(now, minDur(s.cfg.OfflineFor, end.Sub(now)))
+			online = 0
+			continue
+		}
+		// 不跨越取樣點，讓樣本落在整齊的時間上
+		step := minDur(s.cfg.Step, minDur(end.Sub(now), nextSample.Sub(now)))
+		s.count(s.p.Advance(now, step).Finished)
+		s.clk.Advance(step)
+		online += step
+	}
+	if last := s.samples[len(s.samples)-1]; !last.At.Equal(s.clk.Now()) {
+		s.sample(s.clk.Now())
+	}
+}
+
+// act 依策略連續行動，直到策略選擇等待或動作失敗。
+func (s *sim) act(now time.Time) {
+	for i := 0; i < maxActionsPerStep; i++ {
+		a, ok := s.cfg.Policy.Next(s.p.Clone())
+		if !ok {
+			return
+		}
+		if err := a.Apply(&s.p); err != nil {
+			return
+		}
+		s.counts[a.Kind]++
+		lang := a.Lang
+		if lang == "" {
+			lang = s.p.CurrentLanguage
+		}
+		s.events = append(s.events, Event{Minute: s.minute(now), At: now, Kind: string(a.Kind), Lang: lang})
+	}
+}
+
+// keepBusy 讓設定的任務類型持續執行：閒置時立即啟動，並把佇列補滿以便 Advance 在步長內接續。
+func (s *sim) keepBusy(now time.Time) {
+	if s.p.Current == nil || !s.p.Current.IsActive() {
+		_ = s.p.StartTask(s.cfg.Task, now)
+	}
+	for len(s.p.Queue) < player.MaxQueue {
+		if s.p.EnqueueTask(s.cfg.Task) != nil {
+			break
+		}
+	}
+}
+
+// offline 模擬關閉遊戲 d 後重新開啟，以 OfflineCalculator 結算（含 8h 封頂）。
+func (s *sim) offline(now time.Time, d time.Duration) {
+	s.clk.Advance(d)
+	res := s.calc.Compute(&s.p, gametime.Timestamps{WallClockAtClose: now}, s.clk.Now())
+	s.count(res.Finished)
+	detail := d.String()
+	if res.ClampedTo8h {
+		detail += " (clamped to 8h)"
+	}
+	s.events = append(s.events, Event{Minute: s.minute(now), At: now, Kind: "offline", Detail: detail})
+}
+
+func (s *sim) count(finished []player.TaskOutcome) {
+	for _, f := range finished {
+		if f.Success {
+			s.ok++
+		} else {
+			s.failed++
+		}
+	}
+}
+
+func (s *sim) sample(now time.Time) {
+	levels := make(map[string]int, len(s.p.Skills))
+	for lang, sk := range s.p.Skills {
+		levels[lang] = sk.Level
+	}
+	cur := s.p.Skills[s.p.CurrentLanguage]
+	s.samples = append(s.samples, Sample{
+		Minute:          s.minute(now),
+		At:              now,
+		Language:        s.p.CurrentLanguage,
+		Knowledge:       cur.Knowledge,
+		Research:        cur.Research,
+		Levels:          levels,
+		KnowledgePerMin: s.p.KnowledgePerMinute(),
+		ResearchPerMin:  s.p.ResearchPerMinute(),
+		Servers:         s.p.Servers,
+		GPUs:            s.p.GPUs,
+		TasksOK:         s.ok,
+		TasksFailed:     s.failed,
+		Upgrades:        s.counts[advisor.Upgrade],
+		Purchases:       s.counts[advisor.BuyServer] + s.counts[advisor.BuyGPU],
+		Switches:        s.counts[advisor.Switch],
+	})
+}
+
+func (s *sim) minute(now time.Time) float64 { return now.Sub(s.cfg.Epoch).Minutes() }
+
+func minDur(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Languages 回傳樣本中出現過的語言（排序後），供輸出固定的等級欄位。
+func (r Result) Languages() []string {
+	seen := map[string]bool{}
+	for _, smp := range r.Samples {
+		for lang := range smp.Levels {
+			seen[lang] = true
+		}
+	}
+	out := make([]string, 0, len(seen))
+	for lang := range seen {
+		out = append(out, lang)
+	}
+	sort.Strings(out)
+	return out
+}
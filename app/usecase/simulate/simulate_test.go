@@ -0,0 +1,63 @@
+package simulate
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-ddd-architecture/app/domain/advisor"
+)
+
+func TestRun_DeterministicAndSampled(t *testing.T) {
+	cfg := Config{Duration: 2 * time.Hour, SampleEvery: 30 * time.Minute, Policy: advisor.GreedyUpgrade}
+	a, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	b, _ := Run(cfg)
+	if !reflect.DeepEqual(a.Samples, b.Samples) || !reflect.DeepEqual(a.Events, b.Events) {
+		t.Fatalf("same config should give the same run")
+	}
+	if len(a.Samples) != 5 || a.Samples[4].Minute != 120 {
+		t.Fatalf("expected samples at 0,30,...,120 minutes, got %d (last %+v)", len(a.Samples), a.Samples[len(a.Samples)-1])
+	}
+	last := a.Samples[4]
+	if last.TasksOK == 0 || last.Upgrades == 0 || last.Levels["go"] != last.Upgrades {
+		t.Fatalf("greedy-upgrade should finish tasks and level up: %+v", last)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, a); err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 6 || !strings.HasSuffix(lines[0], ",level_go") {
+		t.Fatalf("unexpected csv:\n%s", buf.String())
+	}
+}
+
+func TestRun_OfflinePeriodsUseCalculatorClamp(t *testing.T) {
+	res, err := Run(Config{
+		Duration:     12 * time.Hour,
+		Policy:       advisor.Idle,
+		OfflineEvery: time.Hour,
+		OfflineFor:   10 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	var offline []Event
+	for _, e := range res.Events {
+		if e.Kind == "offline" {
+			offline = append(offline, e)
+		}
+	}
+	if len(offline) != 1 || !strings.Contains(offline[0].Detail, "clamped") {
+		t.Fatalf("expected one clamped 10h offline period (1h online + 10h offline + 1h online): %+v", offline)
+	}
+	if last := res.Samples[len(res.Samples)-1]; last.Minute != 720 || last.Upgrades != 0 {
+		t.Fatalf("idle run should end at 12h without actions: %+v", last)
+	}
+}
@@ -0,0 +1,99 @@
+package gameclient
+
+import (
+	"net/http"
+	"sync"
+
+	"go-ddd-architecture/internal/apitoken"
+)
+
+// tokenSource 提供請求使用的 token：預設讀取使用者設定目錄下伺服器產生的 token 檔（admin 範圍）；
+// 收到 401 時重新讀取一次，因為伺服器可能在客戶端啟動後才產生 token。
+type tokenSource struct {
+	path string
+
+	mu    sync.Mutex
+	token string
+	fixed bool
+}
+
+func newTokenSource() *tokenSource {
+	s := &tokenSource{}
+	if p, err := apitoken.DefaultPath(); err == nil {
+		s.path = p
+		s.reload()
+	}
+	return s
+}
+
+func (s *tokenSource) get() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+func (s *tokenSource) set(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token, s.fixed = token, true
+}
+
+// reload 重新讀取 token 檔，回傳 token 是否改變；以 SetToken 指定時不讀檔。
+func (s *tokenSource) reload() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fixed || s.path == "" {
+		return false
+	}
+	t, err := apitoken.Load(s.path)
+	if err != nil || t.Admin == s.token {
+		return false
+	}
+	s.token = t.Admin
+	return true
+}
+
+// header 回傳附上 Authorization 的標頭（WebSocket 握手使用）。
+func (s *tokenSource) header() http.Header {
+	h := http.Header{}
+	if tok := s.get(); tok != "" {
+		h.Set("Authorization", "Bearer "+tok)
+	}
+	return h
+}
+
+// authTransport 為每個請求附上 Bearer token；401 且 token 檔已更新時以新 token 重送一次。
+type authTransport struct {
+	next http.RoundTripper
+	auth *tokenSource
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if (req.Body != nil && req.GetBody == nil) || !t.auth.reload() {
+		return resp, nil
+	}
+	resp.Body.Close()
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry.Body = body
+	}
+	return t.send(retry)
+}
+
+func (t *authTransport) send(req *http.Request) (*http.Response, error) {
+	tok := t.auth.get()
+	if tok == "" {
+		return t.next.RoundTrip(req)
+	}
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "Bearer "+tok)
+	return t.next.RoundTrip(r)
+}
@@ -0,0 +1,46 @@
+package gameclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-ddd-architecture/internal/apitoken"
+)
+
+// 伺服器在客戶端啟動後才產生 token 檔：第一次 401 後應重新讀檔並以新 token 重送。
+func TestClient_ReloadsTokenFileOn401(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	path, err := apitoken.DefaultPath()
+	if err != nil {
+		t.Skip(err)
+	}
+	var want string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+want {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"code":"unauthorized","message":"missing or invalid API token"}}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	tokens, _, err := apitoken.LoadOrCreate(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = tokens.Admin
+	if _, err := c.GetViewModel(context.Background()); err != nil {
+		t.Fatalf("request after token file appeared: %v", err)
+	}
+
+	c.SetToken("stale")
+	_, err = c.GetViewModel(context.Background())
+	if !IsUnauthorized(err) {
+		t.Fatalf("explicit token must not be replaced by the file: %v", err)
+	}
+}
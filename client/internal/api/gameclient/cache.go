@@ -0,0 +1,36 @@
+package gameclient
+
+import (
+	"sync"
+	"time"
+)
+
+// vmCache 保存最近一次取得的 ViewModel 與其 ETag。
+// 伺服器的 ETag 為狀態版本，剩餘秒數不會使版本改變，因此回傳快取時依經過時間遞減 RemainingSeconds。
+type vmCache struct {
+	mu   sync.Mutex
+	vm   ViewModel
+	etag string
+	at   time.Time
+}
+
+func (c *vmCache) get() (ViewModel, string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.etag == "" {
+		return ViewModel{}, "", false
+	}
+	vm := c.vm
+	if vm.CurrentTask != nil {
+		t := *vm.CurrentTask
+		t.RemainingSeconds = max(0, t.RemainingSeconds-int64(time.Since(c.at)/time.Second))
+		vm.CurrentTask = &t
+	}
+	return vm, c.etag, true
+}
+
+func (c *vmCache) set(vm ViewModel, etag string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.vm, c.etag, c.at = vm, etag, time.Now()
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -13,29 +14,64 @@ import (
 type Client struct {
 	base string
 	hc   *http.Client
+	// stream 用於長連線（SSE），不設整體逾時
+	stream *http.Client
+	// auth 為所有請求附上 API token（預設自動讀取伺服器產生的 token 檔）
+	auth *tokenSource
+	// vmCache 保存最近一次的 ViewModel 與 ETag，供條件式 GET 使用
+	vmCache vmCache
 }
 
 func New(baseURL string) *Client {
+	auth := newTokenSource()
+	tr := &idempotencyTransport{next: &authTransport{next: http.DefaultTransport, auth: auth}}
 	return &Client{
-		base: baseURL,
-		hc:   &http.Client{Timeout: 3 * time.Second},
+		base:   baseURL,
+		hc:     &http.Client{Timeout: 3 * time.Second, Transport: tr},
+		stream: &http.Client{Transport: tr},
+		auth:   auth,
 	}
 }
 
+// SetToken 改用指定的 token（不再讀取 token 檔）；空字串表示不送出 token。
+func (c *Client) SetToken(token string) { c.auth.set(token) }
+
+// TokenFile 回傳自動讀取的 token 檔路徑（供錯誤提示使用）。
+func (c *Client) TokenFile() string { return c.auth.path }
+
+// GetViewModel 取得目前的 ViewModel；帶上次的 ETag 做條件式 GET，未變更（304）時回傳快取。
 func (c *Client) GetViewModel(ctx context.Context) (ViewModel, error) {
+	return c.getViewModel(ctx, c.hc, "")
+}
+
+// WaitViewModel 長輪詢：狀態版本與上次取得的相同時，伺服器最多等待 wait 才回應；
+// 期間有變更立即回傳新的 ViewModel，逾時則回傳快取。尚無快取時等同 GetViewModel。
+func (c *Client) WaitViewModel(ctx context.Context, wait time.Duration) (ViewModel, error) {
+	return c.getViewModel(ctx, c.stream, "?wait="+wait.String())
+}
+
+func (c *Client) getViewModel(ctx context.Context, hc *http.Client, query string) (ViewModel, error) {
 	var vm ViewModel
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, c.base+"/api/v1/game/viewmodel", nil)
-	resp, err := c.hc.Do(req)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, c.base+"/api/v1/game/viewmodel"+query, nil)
+	cached, etag, ok := c.vmCache.get()
+	if ok {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := hc.Do(req)
 	if err != nil {
 		return vm, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified && ok {
+		return cached, nil
+	}
 	if resp.StatusCode >= 400 {
 		return vm, decodeAPIError(resp)
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&vm); err != nil {
 		return vm, err
 	}
+	c.vmCache.set(vm, resp.Header.Get("ETag"))
 	return vm, nil
 }
 
@@ -219,21 +255,224 @@ func (c *Client) PostBuyGPU(ctx context.Context) (ViewModel, error) {
 	return out.ViewModel, nil
 }
 
+// PostAutomation 設定伺服器端自動化（自動結算、自動 Practice）。
+func (c *Client) PostAutomation(ctx context.Context, enabled, autoPractice bool) (ViewModel, error) {
+	var vm ViewModel
+	body, _ := json.Marshal(map[string]bool{"enabled": enabled, "autoPractice": autoPractice})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, c.base+"/api/v1/game/automation", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return vm, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return vm, decodeAPIError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&vm); err != nil {
+		return vm, err
+	}
+	return vm, nil
+}
+
+// PostCommand 透過 /commands 執行命令（如 "buy" + {"item":"gpu"}）；新操作不需新增專屬方法。
+func (c *Client) PostCommand(ctx context.Context, name string, args any) (CommandResponse, error) {
+	var out CommandResponse
+	body, _ := json.Marshal(map[string]any{"command": name, "args": args})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, c.base+"/api/v1/game/commands", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return out, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return out, decodeAPIError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// PostCommandBatch 原子地執行多個命令（全部成功才生效）。
+// 某步驟失敗時仍回傳各步驟結果與最新 ViewModel，並以 *APIErrorErr 回報失敗原因。
+func (c *Client) PostCommandBatch(ctx context.Context, cmds []BatchCommand) (BatchResponse, error) {
+	var out BatchResponse
+	body, _ := json.Marshal(map[string]any{"commands": cmds})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, c.base+"/api/v1/game/commands/batch", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return out, err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil && resp.StatusCode < 400 {
+		return out, err
+	}
+	if resp.StatusCode >= 400 {
+		if out.Error == nil {
+			return out, errors.New(resp.Status)
+		}
+		return out, &APIErrorErr{Status: resp.StatusCode, Code: out.Error.Code, Message: out.Error.Message}
+	}
+	return out, nil
+}
+
+// PostAckNotice 確認通知；id 為空字串時確認全部。
+func (c *Client) PostAckNotice(ctx context.Context, id string) (ViewModel, error) {
+	var vm ViewModel
+	payload := map[string]any{"id": id}
+	if id == "" {
+		payload = map[string]any{"all": true}
+	}
+	body, _ := json.Marshal(payload)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, c.base+"/api/v1/game/notices/ack", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return vm, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return vm, decodeAPIError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&vm); err != nil {
+		return vm, err
+	}
+	return vm, nil
+}
+
+// PostEnqueueTask 將任務（Practice/Targeted/Deploy/Research）加入伺服器端佇列。
+func (c *Client) PostEnqueueTask(ctx context.Context, taskType string) (ViewModel, error) {
+	var vm ViewModel
+	body, _ := json.Marshal(map[string]string{"type": taskType})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, c.base+"/api/v1/game/enqueue", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return vm, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return vm, decodeAPIError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&vm); err != nil {
+		return vm, err
+	}
+	return vm, nil
+}
+
+// GetHistory 取得統計時間序列；res 為 minute/hour/day。
+func (c *Client) GetHistory(ctx context.Context, res string) (History, error) {
+	var out History
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, c.base+"/api/v1/game/history?res="+url.QueryEscape(res), nil)
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return out, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return out, decodeAPIError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetExplain 取得各任務類型的成功率、時長與獎勵拆解；lang 為空時以目前語言計算。
+func (c *Client) GetExplain(ctx context.Context, lang string) (Explain, error) {
+	var out Explain
+	u := c.base + "/api/v1/game/explain"
+	if lang != "" {
+		u += "?lang=" + url.QueryEscape(lang)
+	}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return out, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return out, decodeAPIError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetAdvice 取得進度預測與依 ROI 排序的建議。
+func (c *Client) GetAdvice(ctx context.Context) (Advice, error) {
+	var out Advice
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, c.base+"/api/v1/game/advice", nil)
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return out, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return out, decodeAPIError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
 func decodeAPIError(resp *http.Response) error {
 	var env ErrorEnvelope
 	_ = json.NewDecoder(resp.Body).Decode(&env)
 	if env.Error.Code == "" {
 		return errors.New(resp.Status)
 	}
-	return &APIErrorErr{Code: env.Error.Code, Message: env.Error.Message}
+	return &APIErrorErr{Status: resp.StatusCode, Code: env.Error.Code, Message: env.Error.Message}
 }
 
 // APIErrorErr 是型別化的 API 錯誤，便於前端依錯誤碼做細緻處理。
 type APIErrorErr struct {
+	Status  int
 	Code    string
 	Message string
 }
 
+// 伺服器回傳的穩定錯誤碼（違反遊戲規則，409/422；驗證失敗 401/403；超出速率限制 429）。
+const (
+	CodeUnauthorized          = "unauthorized"
+	CodeForbidden             = "forbidden"
+	CodeRateLimited           = "rate_limited"
+	CodeTaskAlreadyActive     = "task_already_active"
+	CodeQueueFull             = "queue_full"
+	CodeNoFreeSlot            = "no_free_slot"
+	CodeInsufficientKnowledge = "insufficient_knowledge"
+	CodeInsufficientResearch  = "insufficient_research"
+	CodeLanguageLocked        = "language_locked"
+	CodeUnknownTaskType       = "unknown_task_type"
+	CodeNoticeNotFound        = "notice_not_found"
+)
+
+// ErrorCode 取出 API 錯誤碼；非 API 錯誤（如連線失敗）回傳空字串。
+func ErrorCode(err error) string {
+	var apiErr *APIErrorErr
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return ""
+}
+
+// IsRuleViolation 判斷是否為遊戲規則拒絕（409/422），此類錯誤應提示玩家而非視為連線異常。
+func IsRuleViolation(err error) bool {
+	var apiErr *APIErrorErr
+	return errors.As(err, &apiErr) && (apiErr.Status == http.StatusConflict || apiErr.Status == http.StatusUnprocessableEntity)
+}
+
+// IsUnauthorized 判斷是否為 token 缺少或無效（401）；此時不應重試，需提示使用者檢查 token 檔或伺服器。
+func IsUnauthorized(err error) bool {
+	var apiErr *APIErrorErr
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusUnauthorized
+}
+
 func (e *APIErrorErr) Error() string {
 	if e == nil {
 		return ""
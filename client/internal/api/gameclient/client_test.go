@@ -0,0 +1,63 @@
+package gameclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	game "go-ddd-architecture/app/adapter/in/httpserver/game"
+	"go-ddd-architecture/app/domain/gametime"
+	"go-ddd-architecture/app/infra/clock"
+	"go-ddd-architecture/app/infra/memory"
+	usecase "go-ddd-architecture/app/usecase/game"
+)
+
+func TestClient_ViewModelConditionalAndLongPoll(t *testing.T) {
+	clk := clock.SystemClock{}
+	uc := usecase.NewInteractor(memory.NewInMemoryRepo(), clk, gametime.NewOfflineCalculator())
+	if err := uc.Initialize(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	hist := usecase.NewHistoryService(uc, memory.NewInMemoryHistory(), clk)
+	router := game.NewRouter(game.NewHandler(uc, hist, clk, zap.NewNop()), nil, game.RouterOptions{}).Handler()
+	var notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, r)
+		if rec.Code == http.StatusNotModified {
+			notModified.Add(1)
+		}
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes())
+	}))
+	defer srv.Close()
+	c := New(srv.URL)
+	c.SetToken("")
+	ctx := context.Background()
+
+	first, err := c.GetViewModel(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := c.GetViewModel(ctx)
+	if err != nil || notModified.Load() != 1 || again.Knowledge != first.Knowledge {
+		t.Fatalf("second GET should be served from cache via 304: err=%v 304s=%d", err, notModified.Load())
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = uc.StartPractice(clk.Now())
+	}()
+	vm, err := c.WaitViewModel(ctx, 5*time.Second)
+	if err != nil || vm.CurrentTask == nil {
+		t.Fatalf("long-poll should return the started task: %+v %v", vm.CurrentTask, err)
+	}
+}
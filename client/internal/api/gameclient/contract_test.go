@@ -0,0 +1,218 @@
+package gameclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	game "go-ddd-architecture/app/adapter/in/httpserver/game"
+	"go-ddd-architecture/app/domain/gametime"
+	"go-ddd-architecture/app/infra/clock"
+	"go-ddd-architecture/app/infra/memory"
+	usecase "go-ddd-architecture/app/usecase/game"
+)
+
+// 契約測試：以伺服器實際提供的 OpenAPI 文件比對本套件的鏡像型別，
+// 任一方新增、移除或改變欄位型別（例如大小寫不同的 JSON 鍵）時測試即失敗。
+
+func fetchSpec(t *testing.T) map[string]any {
+	t.Helper()
+	clk := clock.SystemClock{}
+	uc := usecase.NewInteractor(memory.NewInMemoryRepo(), clk, gametime.NewOfflineCalculator())
+	if err := uc.Initialize(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	hist := usecase.NewHistoryService(uc, memory.NewInMemoryHistory(), clk)
+	srv := httptest.NewServer(game.NewRouter(game.NewHandler(uc, hist, clk, zap.NewNop()), nil, game.RouterOptions{}).Handler())
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + game.PathOpenAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var spec map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+func TestContract_ResponsesMatchServerSchema(t *testing.T) {
+	spec := fetchSpec(t)
+	cases := []struct {
+		method, path string
+		typ          any
+	}{
+		{"get", "/viewmodel", ViewModel{}},
+		{"post", "/claim-offline", ClaimOfflineResponse{}},
+		{"post", "/try-finish", FinishResponse{}},
+		{"post", "/commands", CommandResponse{}},
+		{"post", "/commands/batch", BatchResponse{}},
+		{"get", "/history", History{}},
+		{"get", "/explain", Explain{}},
+		{"get", "/advice", Advice{}},
+	}
+	c := contract{spec: spec}
+	for _, tc := range cases {
+		op := operation(t, spec, tc.method, tc.path)
+		schema := op["responses"].(map[string]any)["200"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"]
+		for _, p := range c.compare(reflect.TypeOf(tc.typ), schema, tc.path, true) {
+			t.Errorf("%s %s: %s", strings.ToUpper(tc.method), tc.path, p)
+		}
+	}
+	errSchema := map[string]any{"$ref": "#/components/schemas/ErrorEnvelope"}
+	for _, p := range c.compare(reflect.TypeOf(ErrorEnvelope{}), errSchema, "ErrorEnvelope", true) {
+		t.Error(p)
+	}
+}
+
+// 請求只檢查單向：客戶端送出的欄位都必須是伺服器認得的欄位。
+func TestContract_RequestsUseServerFields(t *testing.T) {
+	spec := fetchSpec(t)
+	c := contract{spec: spec}
+	cases := []struct {
+		path string
+		typ  any
+	}{
+		{"/claim-offline", ClaimOfflineRequest{}},
+		{"/commands", BatchCommand{}},
+	}
+	for _, tc := range cases {
+		schema := requestSchema(t, spec, tc.path)
+		for _, p := range c.compare(reflect.TypeOf(tc.typ), schema, tc.path, false) {
+			t.Errorf("POST %s: %s", tc.path, p)
+		}
+	}
+	batch := c.resolve(requestSchema(t, spec, "/commands/batch"))
+	items := batch["properties"].(map[string]any)["commands"].(map[string]any)["items"]
+	for _, p := range c.compare(reflect.TypeOf(BatchCommand{}), items, "/commands/batch", false) {
+		t.Errorf("POST /commands/batch: %s", p)
+	}
+}
+
+func operation(t *testing.T, spec map[string]any, method, path string) map[string]any {
+	t.Helper()
+	ops, ok := spec["paths"].(map[string]any)[game.PrefixV1+path].(map[string]any)
+	if !ok {
+		t.Fatalf("path %s not in spec", path)
+	}
+	op, ok := ops[method].(map[string]any)
+	if !ok {
+		t.Fatalf("%s %s not in spec", method, path)
+	}
+	return op
+}
+
+func requestSchema(t *testing.T, spec map[string]any, path string) any {
+	t.Helper()
+	body := operation(t, spec, "post", path)["requestBody"].(map[string]any)
+	return body["content"].(map[string]any)["application/json"].(map[string]any)["schema"]
+}
+
+type contract struct{ spec map[string]any }
+
+// resolve 展開 $ref 與 allOf（nullable 包裝），回傳實際的 schema。
+func (c contract) resolve(schema any) map[string]any {
+	s, _ := schema.(map[string]any)
+	for {
+		if ref, ok := s["$ref"].(string); ok {
+			name := strings.TrimPrefix(ref, "#/components/schemas/")
+			s, _ = c.spec["components"].(map[string]any)["schemas"].(map[string]any)[name].(map[string]any)
+			continue
+		}
+		if all, ok := s["allOf"].([]any); ok && len(all) == 1 {
+			s, _ = all[0].(map[string]any)
+			continue
+		}
+		return s
+	}
+}
+
+// compare 比對 Go 型別與 schema；strict 時伺服器有而客戶端沒有的欄位也視為漂移。
+func (c contract) compare(t reflect.Type, schema any, at string, strict bool) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	s := c.resolve(schema)
+	typ, _ := s["type"].(string)
+	if t == reflect.TypeOf(json.RawMessage{}) || t.Kind() == reflect.Interface {
+		if typ != "" {
+			return []string{at + ": client accepts any JSON but server declares " + typ}
+		}
+		return nil
+	}
+	mismatch := func() []string { return []string{at + ": client " + t.String() + " vs server " + typ} }
+	switch t.Kind() {
+	case reflect.Bool:
+		if typ != "boolean" {
+			return mismatch()
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if typ != "integer" {
+			return mismatch()
+		}
+	case reflect.Float32, reflect.Float64:
+		if typ != "number" && typ != "integer" {
+			return mismatch()
+		}
+	case reflect.String:
+		if typ != "string" {
+			return mismatch()
+		}
+	case reflect.Slice:
+		if typ != "array" {
+			return mismatch()
+		}
+		return c.compare(t.Elem(), s["items"], at+"[]", strict)
+	case reflect.Map:
+		extra, ok := s["additionalProperties"]
+		if typ != "object" || !ok {
+			return mismatch()
+		}
+		return c.compare(t.Elem(), extra, at+"{}", strict)
+	case reflect.Struct:
+		if typ != "object" {
+			return mismatch()
+		}
+		props, _ := s["properties"].(map[string]any)
+		var out []string
+		seen := map[string]bool{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if !f.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			seen[name] = true
+			ps, ok := props[name]
+			if !ok {
+				out = append(out, at+"."+name+": client field not in server schema")
+				continue
+			}
+			out = append(out, c.compare(f.Type, ps, at+"."+name, strict)...)
+		}
+		if strict {
+			var missing []string
+			for name := range props {
+				if !seen[name] {
+					missing = append(missing, name)
+				}
+			}
+			sort.Strings(missing)
+			for _, name := range missing {
+				out = append(out, at+"."+name+": server field missing from client type")
+			}
+		}
+		return out
+	}
+	return nil
+}
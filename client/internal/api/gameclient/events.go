@@ -0,0 +1,157 @@
+package gameclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// streamIdle 為串流無任何資料（含心跳）多久視為斷線；伺服器心跳間隔為 15s。
+const streamIdle = 45 * time.Second
+
+// Subscribe 連線 /events（SSE），每收到快照或差異就以合併後的 ViewModel 呼叫 onVM。
+// 斷線時依伺服器建議的間隔自動重連並帶上 Last-Event-ID，只補收期間的差異；直到 ctx 結束才返回。
+// onErr 可為 nil；每次連線中斷時呼叫（UI 可藉此退回輪詢）。
+func (c *Client) Subscribe(ctx context.Context, onVM func(ViewModel), onErr func(error)) error {
+	s := &subscription{c: c, onVM: onVM, retry: 2 * time.Second}
+	for {
+		err := s.stream(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if onErr != nil {
+			onErr(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.retry):
+		}
+	}
+}
+
+type subscription struct {
+	c      *Client
+	onVM   func(ViewModel)
+	retry  time.Duration
+	lastID string
+	// doc 為目前的 ViewModel（JSON 物件形式），差異以 JSON Merge Patch 套用其上
+	doc map[string]any
+}
+
+func (s *subscription) stream(parent context.Context) error {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, s.c.base+"/api/v1/game/events", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	if s.lastID != "" && s.doc != nil {
+		req.Header.Set("Last-Event-ID", s.lastID)
+	}
+	// 串流不可套用一般請求的逾時，改以閒置計時器偵測斷線
+	resp, err := s.c.stream.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return decodeAPIError(resp)
+	}
+	idle := time.AfterFunc(streamIdle, cancel)
+	defer idle.Stop()
+
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	var id, event string
+	var data []string
+	for sc.Scan() {
+		idle.Reset(streamIdle)
+		line := sc.Text()
+		if line == "" {
+			if len(data) > 0 {
+				if err := s.dispatch(id, event, strings.Join(data, "\n")); err != nil {
+					return err
+				}
+			}
+			id, event, data = "", "", nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // 心跳
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			id = value
+		case "event":
+			event = value
+		case "data":
+			data = append(data, value)
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				s.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	return errors.New("event stream closed")
+}
+
+func (s *subscription) dispatch(id, event, data string) error {
+	var v map[string]any
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		return err
+	}
+	switch event {
+	case "snapshot":
+		s.doc = v
+	case "diff":
+		if s.doc == nil {
+			return errors.New("diff received before snapshot")
+		}
+		s.doc = applyMergePatch(s.doc, v).(map[string]any)
+	default:
+		return nil
+	}
+	if id != "" {
+		s.lastID = id
+	}
+	b, err := json.Marshal(s.doc)
+	if err != nil {
+		return err
+	}
+	var vm ViewModel
+	if err := json.Unmarshal(b, &vm); err != nil {
+		return fmt.Errorf("decode viewmodel: %w", err)
+	}
+	s.onVM(vm)
+	return nil
+}
+
+// applyMergePatch 套用 JSON Merge Patch（RFC 7386）：物件逐欄合併、null 表示刪除，其餘直接取代。
+func applyMergePatch(target, patch any) any {
+	pm, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	tm, ok := target.(map[string]any)
+	if !ok {
+		tm = map[string]any{}
+	}
+	for k, v := range pm {
+		if v == nil {
+			delete(tm, k)
+			continue
+		}
+		tm[k] = applyMergePatch(tm[k], v)
+	}
+	return tm
+}
@@ -0,0 +1,65 @@
+package gameclient
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	game "go-ddd-architecture/app/adapter/in/httpserver/game"
+	"go-ddd-architecture/app/domain/gametime"
+	"go-ddd-architecture/app/infra/clock"
+	"go-ddd-architecture/app/infra/memory"
+	usecase "go-ddd-architecture/app/usecase/game"
+)
+
+// 訂閱先收到快照，之後的變更以差異合併成完整 ViewModel。
+func TestClient_SubscribeMergesDiffs(t *testing.T) {
+	clk := clock.SystemClock{}
+	uc := usecase.NewInteractor(memory.NewInMemoryRepo(), clk, gametime.NewOfflineCalculator())
+	if err := uc.Initialize(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	hist := usecase.NewHistoryService(uc, memory.NewInMemoryHistory(), clk)
+	srv := httptest.NewServer(game.NewRouter(game.NewHandler(uc, hist, clk, zap.NewNop()), nil, game.RouterOptions{}).Handler())
+	defer srv.Close()
+	c := New(srv.URL)
+	c.SetToken("")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	vms := make(chan ViewModel, 16)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = c.Subscribe(ctx, func(vm ViewModel) {
+			select {
+			case vms <- vm:
+			case <-ctx.Done():
+			}
+		}, nil)
+	}()
+	defer func() { cancel(); <-done }()
+
+	if first := <-vms; first.CurrentTask != nil || len(first.Languages) == 0 {
+		t.Fatalf("unexpected snapshot: %+v", first)
+	}
+	if _, err := c.PostSelectLanguage(ctx, "py"); err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	if _, err := c.PostStartPractice(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	for {
+		select {
+		case vm := <-vms:
+			if vm.CurrentLanguage == "py" && vm.CurrentTask != nil && len(vm.Languages) > 0 {
+				return
+			}
+		case <-ctx.Done():
+			t.Fatalf("merged ViewModel never reflected the changes")
+		}
+	}
+}
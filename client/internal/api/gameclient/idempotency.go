@@ -0,0 +1,62 @@
+package gameclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+const headerIdempotencyKey = "Idempotency-Key"
+
+// idempotencyKeyCtx 為 context 中保存呼叫端指定金鑰的鍵。
+type idempotencyKeyCtx struct{}
+
+// WithIdempotencyKey 讓 ctx 下的 POST 使用指定的 Idempotency-Key。
+// 呼叫端重試同一個邏輯動作（例如逾時後重送購買）時應沿用同一個 ctx 金鑰，伺服器才能辨識為重複請求；
+// 一個金鑰只能用於同一個請求（相同路徑與本體），否則伺服器回 422。
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+// NewIdempotencyKey 產生隨機金鑰（128 位元，十六進位）。
+func NewIdempotencyKey() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// idempotencyTransport 為每個 POST 附上 Idempotency-Key（ctx 未指定時自動產生）；請求送出後連線中斷（回應遺失）時，
+// 以相同金鑰重送一次：伺服器若已執行過會重播原回應，不會重複購買或升級。
+// 逾時或取消（context 結束）不重送，避免超出呼叫端的時限；由呼叫端以 WithIdempotencyKey 沿用金鑰重試。
+type idempotencyTransport struct {
+	next http.RoundTripper
+}
+
+func (t *idempotencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPost || req.Header.Get(headerIdempotencyKey) != "" {
+		return t.next.RoundTrip(req)
+	}
+	key, _ := req.Context().Value(idempotencyKeyCtx{}).(string)
+	if key == "" {
+		key = NewIdempotencyKey()
+	}
+	r := req.Clone(req.Context())
+	r.Header.Set(headerIdempotencyKey, key)
+	resp, err := t.next.RoundTrip(r)
+	if err == nil || req.Context().Err() != nil {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, err
+	}
+	retry := r.Clone(req.Context())
+	if req.GetBody != nil {
+		body, gerr := req.GetBody()
+		if gerr != nil {
+			return nil, err
+		}
+		retry.Body = body
+	}
+	return t.next.RoundTrip(retry)
+}
@@ -0,0 +1,79 @@
+package gameclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-ddd-architecture/app/adapter/in/httpserver"
+)
+
+// 伺服器已執行購買但回應在途中遺失：客戶端以相同金鑰重送，伺服器重播結果而不再扣款。
+func TestClient_RetriesLostResponseWithSameIdempotencyKey(t *testing.T) {
+	var purchases, attempts atomic.Int32
+	keys := make(chan string, 2)
+	buy := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		purchases.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true,"viewModel":{"Servers":2}}`))
+	})
+	idem := httpserver.NewIdempotencyMiddleware(time.Minute, 10)(buy)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys <- r.Header.Get("Idempotency-Key")
+		if attempts.Add(1) == 1 {
+			idem.ServeHTTP(httptest.NewRecorder(), r)
+			conn, _, _ := http.NewResponseController(w).Hijack()
+			conn.Close()
+			return
+		}
+		idem.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	c.SetToken("")
+	vm, err := c.PostBuyServer(context.Background())
+	if err != nil || vm.Servers != 2 {
+		t.Fatalf("buy: %+v %v", vm, err)
+	}
+	if first, second := <-keys, <-keys; first == "" || first != second {
+		t.Fatalf("retry should reuse the key: %q %q", first, second)
+	}
+	if purchases.Load() != 1 {
+		t.Fatalf("purchase executed %d times", purchases.Load())
+	}
+}
+
+// 逾時後由呼叫端重試：沿用 WithIdempotencyKey 的金鑰，伺服器等待原請求完成後重播，不會購買兩次。
+func TestClient_CallerRetryAfterTimeoutReusesKey(t *testing.T) {
+	var purchases atomic.Int32
+	buy := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if purchases.Add(1) == 1 {
+			time.Sleep(300 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true,"viewModel":{"GPUs":1}}`))
+	})
+	srv := httptest.NewServer(httpserver.NewIdempotencyMiddleware(time.Minute, 10)(buy))
+	defer srv.Close()
+	c := New(srv.URL)
+	c.SetToken("")
+
+	action := WithIdempotencyKey(context.Background(), NewIdempotencyKey())
+	ctx, cancel := context.WithTimeout(action, 50*time.Millisecond)
+	_, err := c.PostBuyGPU(ctx)
+	cancel()
+	if err == nil {
+		t.Fatalf("expected the first attempt to time out")
+	}
+	vm, err := c.PostBuyGPU(action)
+	if err != nil || vm.GPUs != 1 {
+		t.Fatalf("retry: %+v %v", vm, err)
+	}
+	if n := purchases.Load(); n != 1 {
+		t.Fatalf("purchase executed %d times", n)
+	}
+}
@@ -0,0 +1,228 @@
+package gameclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-ddd-architecture/internal/websocket"
+)
+
+// ErrSocketClosed 表示 WebSocket 連線已中斷，尚未完成的請求一律以此失敗。
+// 請求已送出後才中斷時，伺服器可能已執行該命令，不可改用其他途徑重送。
+var ErrSocketClosed = errors.New("socket closed")
+
+// ErrNotSent 表示請求未送出（連線已中斷或寫入失敗），伺服器不可能執行，呼叫端可安全改用 HTTP 重送。
+var ErrNotSent = errors.New("socket request not sent")
+
+// socketIdle 為連線無任何訊息（含伺服器 ping）多久視為斷線；伺服器 ping 間隔為 15s。
+const socketIdle = 45 * time.Second
+
+// Socket 為 /ws 的雙向連線：命令以自動產生的 id 對應結果，狀態推送合併為完整 ViewModel 後回呼。
+// 伺服器保證命令的 result 在反映該命令的狀態推送之後送達，因此 Command 返回時 onVM 已收到最新狀態。
+type Socket struct {
+	conn *websocket.Conn
+	onVM func(ViewModel)
+	seq  atomic.Uint64
+
+	mu      sync.Mutex
+	pending map[string]chan socketReply
+	err     error
+	done    chan struct{}
+
+	// doc 僅由讀取 goroutine 存取
+	doc map[string]any
+}
+
+type socketRequest struct {
+	ID       string         `json:"id"`
+	Type     string         `json:"type"`
+	Command  string         `json:"command,omitempty"`
+	Args     any            `json:"args,omitempty"`
+	Commands []BatchCommand `json:"commands,omitempty"`
+}
+
+type socketReply struct {
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Version uint64          `json:"version"`
+	Result  json.RawMessage `json:"result"`
+	Status  int             `json:"status"`
+	Error   *APIError       `json:"error"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// DialSocket 連線 /ws；onVM 於收到快照或差異時以合併後的 ViewModel 呼叫（在讀取 goroutine 中執行，不可阻塞）。
+// 連線中斷後不自動重連，呼叫端可由 Done 得知並重新連線。
+func (c *Client) DialSocket(ctx context.Context, onVM func(ViewModel)) (*Socket, error) {
+	u := strings.Replace(c.base, "http", "ws", 1) + "/api/v1/game/ws"
+	conn, err := websocket.Dial(ctx, u, c.auth.header())
+	var he *websocket.HandshakeError
+	if errors.As(err, &he) && he.StatusCode == http.StatusUnauthorized && c.auth.reload() {
+		conn, err = websocket.Dial(ctx, u, c.auth.header())
+	}
+	if err != nil {
+		if errors.As(err, &he) {
+			var env ErrorEnvelope
+			if json.Unmarshal(he.Body, &env) == nil && env.Error.Code != "" {
+				return nil, &APIErrorErr{Status: he.StatusCode, Code: env.Error.Code, Message: env.Error.Message}
+			}
+		}
+		return nil, err
+	}
+	s := &Socket{conn: conn, onVM: onVM, pending: map[string]chan socketReply{}, done: make(chan struct{})}
+	go s.readLoop()
+	return s, nil
+}
+
+// Command 經由連線執行命令並等待結果（同 PostCommand 的命令名稱與參數）。
+func (s *Socket) Command(ctx context.Context, name string, args any) (CommandResult, error) {
+	var out CommandResult
+	rep, err := s.roundTrip(ctx, socketRequest{Type: "command", Command: name, Args: args})
+	if err != nil {
+		return out, err
+	}
+	return out, json.Unmarshal(rep.Result, &out)
+}
+
+// Batch 原子地執行多個命令；某步驟失敗時仍回傳各步驟結果，並以 *APIErrorErr 回報失敗原因。
+func (s *Socket) Batch(ctx context.Context, cmds []BatchCommand) (BatchResult, error) {
+	var out BatchResult
+	rep, err := s.roundTrip(ctx, socketRequest{Type: "batch", Commands: cmds})
+	if len(rep.Result) > 0 {
+		_ = json.Unmarshal(rep.Result, &out)
+	}
+	return out, err
+}
+
+// Refresh 要求伺服器重送完整快照（onVM 會在返回前被呼叫）。
+func (s *Socket) Refresh(ctx context.Context) error {
+	_, err := s.roundTrip(ctx, socketRequest{Type: "viewmodel"})
+	return err
+}
+
+// Done 於連線中斷時關閉；之後 Err 回傳中斷原因。
+func (s *Socket) Done() <-chan struct{} { return s.done }
+
+func (s *Socket) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close 關閉連線；尚未完成的請求以 ErrSocketClosed 失敗。
+func (s *Socket) Close() error {
+	return s.conn.Close()
+}
+
+func (s *Socket) roundTrip(ctx context.Context, req socketRequest) (socketReply, error) {
+	req.ID = strconv.FormatUint(s.seq.Add(1), 10)
+	ch := make(chan socketReply, 1)
+	s.mu.Lock()
+	if s.err != nil {
+		err := s.err
+		s.mu.Unlock()
+		return socketReply{}, fmt.Errorf("%w: %w", ErrNotSent, err)
+	}
+	s.pending[req.ID] = ch
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, req.ID)
+		s.mu.Unlock()
+	}()
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return socketReply{}, err
+	}
+	if err := s.conn.WriteMessage(websocket.OpText, b); err != nil {
+		return socketReply{}, fmt.Errorf("%w: %w", ErrNotSent, err)
+	}
+	select {
+	case rep := <-ch:
+		if rep.Type == "error" {
+			if rep.Error == nil {
+				return rep, errors.New("socket request failed")
+			}
+			return rep, &APIErrorErr{Status: rep.Status, Code: rep.Error.Code, Message: rep.Error.Message}
+		}
+		return rep, nil
+	case <-s.done:
+		return socketReply{}, s.Err()
+	case <-ctx.Done():
+		return socketReply{}, ctx.Err()
+	}
+}
+
+func (s *Socket) readLoop() {
+	err := s.read()
+	s.mu.Lock()
+	s.err = fmt.Errorf("%w: %v", ErrSocketClosed, err)
+	s.mu.Unlock()
+	s.conn.Close()
+	close(s.done)
+}
+
+func (s *Socket) read() error {
+	for {
+		_ = s.conn.SetReadDeadline(time.Now().Add(socketIdle))
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		var rep socketReply
+		if err := json.Unmarshal(data, &rep); err != nil {
+			return err
+		}
+		switch rep.Type {
+		case "snapshot", "diff":
+			if err := s.apply(rep); err != nil {
+				return err
+			}
+		case "result", "error":
+			s.mu.Lock()
+			ch := s.pending[rep.ID]
+			s.mu.Unlock()
+			if ch != nil {
+				ch <- rep
+			}
+		}
+	}
+}
+
+// apply 將快照或差異（JSON Merge Patch）合併到目前狀態後回呼 onVM。
+func (s *Socket) apply(rep socketReply) error {
+	var v map[string]any
+	if err := json.Unmarshal(rep.Data, &v); err != nil {
+		return err
+	}
+	if rep.Type == "snapshot" {
+		s.doc = v
+	} else {
+		if s.doc == nil {
+			return errors.New("diff received before snapshot")
+		}
+		s.doc = applyMergePatch(s.doc, v).(map[string]any)
+	}
+	if s.onVM == nil {
+		return nil
+	}
+	b, err := json.Marshal(s.doc)
+	if err != nil {
+		return err
+	}
+	var vm ViewModel
+	if err := json.Unmarshal(b, &vm); err != nil {
+		return fmt.Errorf("decode viewmodel: %w", err)
+	}
+	s.onVM(vm)
+	return nil
+}
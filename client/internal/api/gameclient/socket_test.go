@@ -0,0 +1,42 @@
+package gameclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-ddd-architecture/internal/websocket"
+)
+
+// 命令送出後連線中斷：伺服器可能已執行，錯誤不可標示為未送出（呼叫端不得改用 HTTP 重送）。
+func TestSocket_DropAfterSendIsNotRetryable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		_, _, _ = conn.ReadMessage()
+		_ = conn.Close()
+	}))
+	defer srv.Close()
+	c := New(srv.URL)
+	c.SetToken("")
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	sock, err := c.DialSocket(ctx, func(ViewModel) {})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+
+	_, err = sock.Command(ctx, "buy-gpu", nil)
+	if !errors.Is(err, ErrSocketClosed) || errors.Is(err, ErrNotSent) {
+		t.Fatalf("dropped after send: got %v", err)
+	}
+	<-sock.Done()
+	if _, err := sock.Command(ctx, "buy-gpu", nil); !errors.Is(err, ErrNotSent) {
+		t.Fatalf("closed socket should report not sent, got %v", err)
+	}
+}
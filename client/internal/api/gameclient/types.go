@@ -1,12 +1,14 @@
 package gameclient
 
+import "encoding/json"
+
 // 這些型別 mirror 伺服器的 JSON 結構，避免直接 import usecase 的 DTO（降低耦合）。
 // 若後端 DTO 調整，本層只需做相容處理即可。
 
 type ViewModel struct {
 	Knowledge        int64             `json:"Knowledge"`
 	Research         int64             `json:"Research"`
-	Notices          []string          `json:"Notices"`
+	Notices          []Notice          `json:"Notices"`
 	CurrentTask      *Task             `json:"CurrentTask"`
 	Level            int               `json:"Level"`
 	NextUpgradeCost  int64             `json:"NextUpgradeCost"`
@@ -19,6 +21,61 @@ type ViewModel struct {
 	Servers int `json:"Servers"`
 	GPUs    int `json:"GPUs"`
 	Slots   int `json:"Slots"`
+	// Automation
+	Automation Automation `json:"Automation"`
+	Queue      []string   `json:"Queue"`
+}
+
+// Notice 為伺服器通知中心的訊息（最新在前），需呼叫 PostAckNotice 確認後才會移除。
+type Notice struct {
+	ID        string `json:"ID"`
+	Severity  string `json:"Severity"` // info | warning | error
+	Message   string `json:"Message"`
+	Count     int    `json:"Count"`
+	At        string `json:"At"`
+	ExpiresAt string `json:"ExpiresAt"`
+}
+
+// CommandResult 為 /commands 單一命令的執行結果。
+type CommandResult struct {
+	Command  string `json:"Command"`
+	Finished bool   `json:"Finished"`
+	Reward   int64  `json:"Reward"`
+}
+
+type CommandResponse struct {
+	Result    CommandResult `json:"result"`
+	ViewModel ViewModel     `json:"viewModel"`
+}
+
+// BatchCommand 為批次中的一個步驟（同 /commands 的請求格式）。
+type BatchCommand struct {
+	Command string `json:"command"`
+	Args    any    `json:"args,omitempty"`
+}
+
+type BatchStep struct {
+	Index  int           `json:"Index"`
+	Result CommandResult `json:"Result"`
+	OK     bool          `json:"OK"`
+	Error  string        `json:"Error"`
+}
+
+type BatchResult struct {
+	Applied bool        `json:"Applied"`
+	Steps   []BatchStep `json:"Steps"`
+}
+
+type BatchResponse struct {
+	Error     *APIError   `json:"error"`
+	Result    BatchResult `json:"result"`
+	ViewModel ViewModel   `json:"viewModel"`
+}
+
+type Automation struct {
+	Enabled      bool `json:"Enabled"`
+	AutoPractice bool `json:"AutoPractice"`
+	AutoPlay     bool `json:"AutoPlay"`
 }
 
 type Task struct {
@@ -41,12 +98,24 @@ type ClaimOfflineRequest struct {
 	AsOf string `json:"asOf,omitempty"`
 }
 
+// ClaimOfflineResult 對應伺服器的 gametime.OfflineResult。
 type ClaimOfflineResult struct {
-	DeltaSeconds     int64 `json:"DeltaSeconds"`
-	ClampedSeconds   int64 `json:"ClampedSeconds"`
-	GainedKnowledge  int64 `json:"GainedKnowledge"`
-	GainedResearch   int64 `json:"GainedResearch"`
-	ClampedByCeiling bool  `json:"ClampedByCeiling"`
+	GainedKnowledge int64         `json:"GainedKnowledge"`
+	GainedResearch  int64         `json:"GainedResearch"`
+	ClampedTo8h     bool          `json:"ClampedTo8h"`
+	AnomalyDetected bool          `json:"AnomalyDetected"`
+	Message         string        `json:"Message"`
+	Finished        []TaskOutcome `json:"Finished"`
+}
+
+// TaskOutcome 為一個已結算的任務（離線期間完成者）。
+type TaskOutcome struct {
+	TaskID   string `json:"TaskID"`
+	Type     string `json:"Type"`
+	Language string `json:"Language"`
+	Success  bool   `json:"Success"`
+	Reward   int64  `json:"Reward"`
+	DoneAt   string `json:"DoneAt"`
 }
 
 type ClaimOfflineResponse struct {
@@ -68,3 +137,84 @@ type APIError struct {
 type ErrorEnvelope struct {
 	Error APIError `json:"error"`
 }
+
+type History struct {
+	Resolution string         `json:"Resolution"`
+	Points     []HistoryPoint `json:"Points"`
+}
+
+type HistoryPoint struct {
+	At              string            `json:"At"`
+	Languages       map[string]LangVM `json:"Languages"`
+	KnowledgePerMin int64             `json:"KnowledgePerMin"`
+	ResearchPerMin  int64             `json:"ResearchPerMin"`
+	Servers         int               `json:"Servers"`
+	GPUs            int               `json:"GPUs"`
+}
+
+// Explain 為 /explain 的回應：各任務類型的成功率、時長與獎勵拆解。
+type Explain struct {
+	Language string        `json:"Language"`
+	Tasks    []TaskExplain `json:"Tasks"`
+}
+
+type Factor struct {
+	Name   string  `json:"Name"`
+	Value  float64 `json:"Value"`
+	Detail string  `json:"Detail"`
+}
+
+type TaskExplain struct {
+	Type                string   `json:"Type"`
+	Success             float64  `json:"Success"`
+	SuccessFactors      []Factor `json:"SuccessFactors"`
+	BaseDurationSeconds float64  `json:"BaseDurationSeconds"`
+	DurationSeconds     float64  `json:"DurationSeconds"`
+	DurationFactors     []Factor `json:"DurationFactors"`
+	Reward              int64    `json:"Reward"`
+	ResearchMin         int64    `json:"ResearchMin"`
+	ResearchMax         int64    `json:"ResearchMax"`
+	RewardFactors       []Factor `json:"RewardFactors"`
+	ExpectedKnowledge   float64  `json:"ExpectedKnowledge"`
+	ExpectedResearch    float64  `json:"ExpectedResearch"`
+}
+
+// Advice 為 /advice 的回應：進度預測與依 ROI 排序的建議（最佳在前）。
+type Advice struct {
+	Forecast        Forecast         `json:"Forecast"`
+	Recommendations []Recommendation `json:"Recommendations"`
+}
+
+type Forecast struct {
+	Language        string         `json:"Language"`
+	KnowledgePerMin float64        `json:"KnowledgePerMin"`
+	ResearchPerMin  float64        `json:"ResearchPerMin"`
+	UpgradeCost     int64          `json:"UpgradeCost"`
+	UpgradeMinutes  float64        `json:"UpgradeMinutes"`
+	Store           []ItemForecast `json:"Store"`
+}
+
+type ItemForecast struct {
+	Item          string  `json:"Item"`
+	CostKnowledge int64   `json:"CostKnowledge"`
+	Minutes       float64 `json:"Minutes"`
+	Blocked       string  `json:"Blocked"`
+}
+
+type Recommendation struct {
+	Action               string          `json:"Action"`
+	Language             string          `json:"Language"`
+	Command              string          `json:"Command"`
+	Args                 json.RawMessage `json:"Args"`
+	CostKnowledge        int64           `json:"CostKnowledge"`
+	CostResearch         int64           `json:"CostResearch"`
+	Affordable           bool            `json:"Affordable"`
+	WaitMinutes          float64         `json:"WaitMinutes"`
+	Blocked              string          `json:"Blocked"`
+	DeltaKnowledgePerMin float64         `json:"DeltaKnowledgePerMin"`
+	DeltaResearchPerMin  float64         `json:"DeltaResearchPerMin"`
+	KnowledgeROI         float64         `json:"KnowledgeROI"`
+	ResearchROI          float64         `json:"ResearchROI"`
+	Score                float64         `json:"Score"`
+	Note                 string          `json:"Note"`
+}
@@ -2,11 +2,13 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"image/color"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -33,17 +35,28 @@ type App struct {
 	busy   atomic.Bool // 目前是否有 API 呼叫進行中（避免重複觸發）
 	lastVM time.Time   // 最近更新 VM 的時間
 
-	// 自動完成觸發的去重 key（避免相同任務重複觸發）
-	autoFinishKey string // 格式: taskID|endsAt
-	// 自動 practice 的去重 key，避免在同一週期重複發起
-	autoPracticeKey string
+	// 推播（WebSocket /ws）：連線中時以推播更新 VM，命令也改走同一連線；
+	// 輪詢退為 streamPoll 的低頻備援；斷線時恢復 poll 與 HTTP 命令
+	streamOnce sync.Once
+	streaming  atomic.Bool
+	streamPoll time.Duration
+	sock       atomic.Pointer[gameclient.Socket]
+	// unauthorized 表示最近一次請求的 token 被拒（401），輪詢與重連放慢到 authRetry
+	unauthorized atomic.Bool
+
+	// 伺服器端自動化（自動結算/自動 Practice）是否已於本次啟動時確認開啟
+	automationSynced bool
+	// 上一幀看到的任務（用於偵測伺服器端結算並播放戰鬥動畫）
+	prevTaskKey  string // 格式: taskID|endsAt
+	prevTaskLang string
+	prevTaskK    int64
 
 	// 去抖 Networking 顯示
 	netShowSince   time.Time     // 開始請求的時間
 	netHideDelay   time.Duration // 請求結束後延遲隱藏時間
 	netShowLatency time.Duration // 請求開始延遲顯示的門檻
 
-	// 輕量 Toast 提示（例如升級失敗）
+	// 輕量 Toast 提示（僅限本地 UI 操作；遊戲事件改由伺服器通知顯示）
 	toastMsg   atomic.Value // string
 	toastUntil atomic.Value // time.Time
 
@@ -98,8 +111,18 @@ type App struct {
 	// 本地 Task 視覺預覽（不影響後端）："deploy" | "research" | ""
 	taskPreview string
 
-	// 當前任務進行中時的排隊意圖："deploy" | "research"
-	nextTaskIntent string
+	// 統計面板（S 開關、Tab 切換粒度）；開啟時每 historyPoll 重新取得時間序列
+	showStats   bool
+	statsRes    string // "minute" | "hour" | "day"
+	histBusy    atomic.Bool
+	lastHistory time.Time
+	historyPoll time.Duration
+
+	// 成功率拆解 tooltip（滑鼠停在 Est. Success 上時顯示，停留期間每 explainPoll 更新）
+	hoverExplain bool
+	explainBusy  atomic.Bool
+	lastExplain  time.Time
+	explainPoll  time.Duration
 }
 
 func NewApp(api *gameclient.Client, state *State) *App {
@@ -108,6 +131,7 @@ func NewApp(api *gameclient.Client, state *State) *App {
 		state:          state,
 		face:           loadUIFont(),
 		poll:           250 * time.Millisecond,
+		streamPoll:     5 * time.Second,
 		pulseDur:       300 * time.Millisecond,
 		netHideDelay:   300 * time.Millisecond,
 		netShowLatency: 120 * time.Millisecond,
@@ -115,13 +139,24 @@ func NewApp(api *gameclient.Client, state *State) *App {
 		showHotkeys:    true,
 		langSort:       "lv",
 		lastLangUsedAt: map[string]time.Time{},
+		statsRes:       "minute",
+		historyPoll:    30 * time.Second,
+		explainPoll:    2 * time.Second,
 	}
 }
 
 // Update handles periodic polling and input.
 func (a *App) Update() error {
-	// Periodic viewmodel polling (non-blocking)
-	if time.Since(a.lastVM) >= a.poll && !a.busy.Load() {
+	a.streamOnce.Do(a.startStream)
+	// Periodic viewmodel polling (non-blocking)；推播連線中時僅低頻備援
+	interval := a.poll
+	if a.streaming.Load() {
+		interval = a.streamPoll
+	}
+	if a.unauthorized.Load() {
+		interval = authRetry
+	}
+	if time.Since(a.lastVM) >= interval && !a.busy.Load() {
 		a.busy.Store(true)
 		a.netShowSince = time.Now()
 		go func() {
@@ -130,9 +165,13 @@ func (a *App) Update() error {
 			defer cancel()
 			vm, err := a.api.GetViewModel(ctx)
 			if err != nil {
-				a.state.SetErr(err.Error())
+				a.reportErr(err)
+				if gameclient.IsUnauthorized(err) {
+					a.lastVM = time.Now()
+				}
 				return
 			}
+			a.unauthorized.Store(false)
 			a.state.SetErr("")
 			a.state.SetVM(vm)
 			a.lastVM = time.Now()
@@ -165,6 +204,32 @@ func (a *App) Update() error {
 		return nil
 	}
 
+	// 統計面板：開啟時攔截其餘操作鍵，只處理關閉與粒度切換
+	if inpututil.IsKeyJustPressed(ebiten.KeyS) {
+		a.showStats = !a.showStats
+		if a.showStats {
+			a.lastHistory = time.Time{}
+		}
+	}
+	if a.showStats {
+		if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+			switch a.statsRes {
+			case "minute":
+				a.statsRes = "hour"
+			case "hour":
+				a.statsRes = "day"
+			default:
+				a.statsRes = "minute"
+			}
+			a.lastHistory = time.Time{}
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			a.showStats = false
+		}
+		a.pollHistory()
+		return nil
+	}
+
 	// Toggle hotkeys
 	if inpututil.IsKeyJustPressed(ebiten.KeyH) {
 		a.showHotkeys = !a.showHotkeys
@@ -199,7 +264,7 @@ func (a *App) Update() error {
 			if err == nil {
 				a.state.SetVM(vm)
 			}
-			return err
+			return a.ruleError(err)
 		})
 	}
 	if inpututil.IsKeyJustPressed(ebiten.KeyT) && !a.busy.Load() {
@@ -208,7 +273,7 @@ func (a *App) Update() error {
 			if err == nil {
 				a.state.SetVM(vm)
 			}
-			return err
+			return a.ruleError(err)
 		})
 	}
 
@@ -216,8 +281,14 @@ func (a *App) Update() error {
 	if inpututil.IsKeyJustPressed(ebiten.KeyD) && !a.busy.Load() {
 		vmSnap, _ := a.state.Snapshot()
 		if vmSnap.CurrentTask != nil && vmSnap.CurrentTask.RemainingSeconds > 0 {
-			a.nextTaskIntent = "deploy"
-			a.showToast("Queued: Deploy (will start after current task)")
+			a.trigger(func(ctx context.Context) error {
+				vm, err := a.api.PostEnqueueTask(ctx, "Deploy")
+				if err == nil {
+					a.state.SetVM(vm)
+					a.showToast("Queued: Deploy (will start after current task)")
+				}
+				return a.ruleError(err)
+			})
 		} else {
 			a.trigger(func(ctx context.Context) error {
 				vm, err := a.api.PostStartDeploy(ctx)
@@ -225,15 +296,21 @@ func (a *App) Update() error {
 					a.state.SetVM(vm)
 					a.showToast("Started Deploy")
 				}
-				return err
+				return a.ruleError(err)
 			})
 		}
 	}
 	if inpututil.IsKeyJustPressed(ebiten.KeyR) && !a.busy.Load() {
 		vmSnap, _ := a.state.Snapshot()
 		if vmSnap.CurrentTask != nil && vmSnap.CurrentTask.RemainingSeconds > 0 {
-			a.nextTaskIntent = "research"
-			a.showToast("Queued: Research (will start after current task)")
+			a.trigger(func(ctx context.Context) error {
+				vm, err := a.api.PostEnqueueTask(ctx, "Research")
+				if err == nil {
+					a.state.SetVM(vm)
+					a.showToast("Queued: Research (will start after current task)")
+				}
+				return a.ruleError(err)
+			})
 		} else {
 			a.trigger(func(ctx context.Context) error {
 				vm, err := a.api.PostStartResearch(ctx)
@@ -241,38 +318,41 @@ func (a *App) Update() error {
 					a.state.SetVM(vm)
 					a.showToast("Started Research")
 				}
-				return err
+				return a.ruleError(err)
 			})
 		}
 	}
 
+	// Est. Success hover → 成功率拆解 tooltip
+	mx, my := ebiten.CursorPosition()
+	a.hoverExplain = lastSuccessRect.contains(mx, my)
+	if a.hoverExplain {
+		a.pollExplain()
+	}
+
 	// Mouse click actions for Store Buy buttons
 	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && !a.busy.Load() {
 		mx, my := ebiten.CursorPosition()
 		// Server Buy
 		if mx >= lastServerBuyRect.X && my >= lastServerBuyRect.Y && mx < lastServerBuyRect.X+lastServerBuyRect.W && my < lastServerBuyRect.Y+lastServerBuyRect.H {
 			a.trigger(func(ctx context.Context) error {
-				vm, err := a.api.PostBuyServer(ctx)
+				_, err := a.command(ctx, "buy", map[string]string{"item": "server"})
 				if err == nil {
-					a.state.SetVM(vm)
 					a.showToast("Purchased: Server (+slots)")
 					return nil
 				}
-				a.showToast("Need more Knowledge for Server")
-				return err
+				return a.ruleError(err)
 			})
 		}
 		// GPU Buy
 		if mx >= lastGPUBuyRect.X && my >= lastGPUBuyRect.Y && mx < lastGPUBuyRect.X+lastGPUBuyRect.W && my < lastGPUBuyRect.Y+lastGPUBuyRect.H {
 			a.trigger(func(ctx context.Context) error {
-				vm, err := a.api.PostBuyGPU(ctx)
+				_, err := a.command(ctx, "buy", map[string]string{"item": "gpu"})
 				if err == nil {
-					a.state.SetVM(vm)
 					a.showToast("Purchased: GPU (+Research/min)")
 					return nil
 				}
-				a.showToast("Need slot or Knowledge for GPU")
-				return err
+				return a.ruleError(err)
 			})
 		}
 	}
@@ -295,11 +375,55 @@ func (a *App) Update() error {
 				}
 				return nil
 			}
-			if s := err.Error(); s != "" && (s == "not_enough_research: not enough research" || s == "not_enough_research") {
-				a.showToast("Not enough Research to upgrade")
+			return a.ruleError(err)
+		})
+	}
+	// X: 確認最新通知；Shift+X: 全部確認
+	if inpututil.IsKeyJustPressed(ebiten.KeyX) && !a.busy.Load() {
+		vmSnap, _ := a.state.Snapshot()
+		if len(vmSnap.Notices) > 0 {
+			id := vmSnap.Notices[0].ID
+			if ebiten.IsKeyPressed(ebiten.KeyShift) {
+				id = ""
+			}
+			a.trigger(func(ctx context.Context) error {
+				vm, err := a.api.PostAckNotice(ctx, id)
+				if err == nil {
+					a.state.SetVM(vm)
+				}
+				if gameclient.ErrorCode(err) == gameclient.CodeNoticeNotFound {
+					return nil // 已過期或已被確認
+				}
+				return err
+			})
+		}
+	}
+	// A: 切換伺服器端 auto-play（依 /advice 的最佳建議自動升級/購買），並提示目前最佳建議
+	if inpututil.IsKeyJustPressed(ebiten.KeyA) && !a.busy.Load() {
+		vmSnap, _ := a.state.Snapshot()
+		on := !vmSnap.Automation.AutoPlay
+		a.trigger(func(ctx context.Context) error {
+			_, err := a.command(ctx, "set-automation", map[string]bool{
+				"enabled": true, "autoPractice": true, "autoPlay": on,
+			})
+			if err != nil {
+				return a.ruleError(err)
+			}
+			if !on {
+				a.showToast("Auto-play OFF")
 				return nil
 			}
-			return err
+			msg := "Auto-play ON"
+			if adv, err := a.api.GetAdvice(ctx); err == nil && len(adv.Recommendations) > 0 {
+				top := adv.Recommendations[0]
+				msg += " (next: " + top.Action
+				if top.Language != "" {
+					msg += " " + top.Language
+				}
+				msg += ")"
+			}
+			a.showToast(msg)
+			return nil
 		})
 	}
 	if inpututil.IsKeyJustPressed(ebiten.KeyC) && !a.busy.Load() {
@@ -319,7 +443,7 @@ func (a *App) Update() error {
 				a.state.SetVM(vm)
 				a.showToast("Switched to Go")
 			}
-			return err
+			return a.ruleError(err)
 		})
 	}
 	if inpututil.IsKeyJustPressed(ebiten.Key2) && !a.busy.Load() {
@@ -329,7 +453,7 @@ func (a *App) Update() error {
 				a.state.SetVM(vm)
 				a.showToast("Switched to Python")
 			}
-			return err
+			return a.ruleError(err)
 		})
 	}
 	if inpututil.IsKeyJustPressed(ebiten.Key3) && !a.busy.Load() {
@@ -339,95 +463,135 @@ func (a *App) Update() error {
 				a.state.SetVM(vm)
 				a.showToast("Switched to JavaScript")
 			}
-			return err
+			return a.ruleError(err)
 		})
 	}
 
-	// Auto practice/finish
-	if !a.busy.Load() {
+	// 自動化改由伺服器端排程處理（到時自動結算、接續佇列/Practice）；
+	// 客戶端只需在首次取得 VM 時確認已開啟。
+	if !a.automationSynced && !a.busy.Load() && !a.lastVM.IsZero() {
+		a.automationSynced = true
 		vmSnap, _ := a.state.Snapshot()
-		if vmSnap.CurrentTask == nil {
-			if a.nextTaskIntent != "" && !a.busy.Load() {
-				intent := a.nextTaskIntent
-				// 不使用 autoPracticeKey，避免與 practice 邏輯衝突
-				a.trigger(func(ctx context.Context) error {
-					var (
-						vm  gameclient.ViewModel
-						err error
-					)
-					switch intent {
-					case "deploy":
-						vm, err = a.api.PostStartDeploy(ctx)
-					case "research":
-						vm, err = a.api.PostStartResearch(ctx)
-					default:
-						// fallback: practice
-						vm, err = a.api.PostStartPractice(ctx)
-					}
-					if err == nil {
-						a.state.SetVM(vm)
-						if intent == "deploy" {
-							a.showToast("Started Deploy")
-						}
-						if intent == "research" {
-							a.showToast("Started Research")
-						}
-						a.nextTaskIntent = ""
-					}
-					return err
-				})
-			} else if a.autoPracticeKey == "" {
-				a.autoPracticeKey = "inflight"
-				a.trigger(func(ctx context.Context) error {
-					vm, err := a.api.PostStartPractice(ctx)
-					if err == nil {
-						a.state.SetVM(vm)
-					}
-					return err
-				})
-			}
+		if !vmSnap.Automation.Enabled || !vmSnap.Automation.AutoPractice {
+			a.trigger(func(ctx context.Context) error {
+				vm, err := a.api.PostAutomation(ctx, true, true)
+				if err == nil {
+					a.state.SetVM(vm)
+					return nil
+				}
+				a.automationSynced = false
+				return err
+			})
 		}
-		if vmSnap.CurrentTask != nil && vmSnap.CurrentTask.RemainingSeconds <= 0 {
-			key := vmSnap.CurrentTask.ID + "|" + vmSnap.CurrentTask.EndsAt
-			if key != "" && key != a.autoFinishKey {
-				a.autoFinishKey = key
-				a.trigger(func(ctx context.Context) error {
-					out, err := a.api.PostTryFinish(ctx)
-					if err == nil {
-						a.state.SetVM(out.ViewModel)
-						if out.Finished {
-							if vmSnap.CurrentTask != nil && vmSnap.CurrentTask.Language != "" {
-								a.battleLang = vmSnap.CurrentTask.Language
-							} else {
-								a.battleLang = out.ViewModel.CurrentLanguage
-							}
-							r := rand.New(rand.NewSource(time.Now().UnixNano()))
-							a.battleStyle = 1 + r.Intn(3)
-							if out.Reward > 0 {
-								a.battleType = "success"
-								a.floatText = fmt.Sprintf("+%d K", out.Reward)
-								a.floatStart = time.Now()
-								a.floatUntil = a.floatStart.Add(800 * time.Millisecond)
-							} else {
-								a.battleType = "fail"
-							}
-							a.battleUntil = time.Now().Add(700 * time.Millisecond)
-						}
-					}
-					return err
-				})
+	}
+	return nil
+}
+
+// startStream 背景維持 /ws 連線；斷線時每 2 秒重連（期間退回輪詢與 HTTP 命令）。
+func (a *App) startStream() {
+	go func() {
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			sock, err := a.api.DialSocket(ctx, func(vm gameclient.ViewModel) {
+				a.streaming.Store(true)
+				a.state.SetErr("")
+				a.state.SetVM(vm)
+				a.lastVM = time.Now()
+			})
+			cancel()
+			if err == nil {
+				a.sock.Store(sock)
+				<-sock.Done()
+				a.sock.Store(nil)
+				a.streaming.Store(false)
 			}
-		}
-		if vmSnap.CurrentTask == nil {
-			a.autoFinishKey = ""
-			if a.autoPracticeKey != "" {
-				// keep until next cycle
+			if gameclient.IsUnauthorized(err) {
+				a.reportErr(err)
+				time.Sleep(authRetry)
+				continue
 			}
-		} else {
-			a.autoPracticeKey = ""
+			time.Sleep(2 * time.Second)
+		}
+	}()
+}
+
+// command 執行命令：WebSocket 連線中時走同一連線（結果送達前 VM 已由推播更新），否則改用 POST /commands。
+// 只有命令確定未送出時才改用 HTTP；送出後才斷線則回傳錯誤，避免購買等命令被執行兩次。
+func (a *App) command(ctx context.Context, name string, args any) (gameclient.CommandResult, error) {
+	if sock := a.sock.Load(); sock != nil {
+		res, err := sock.Command(ctx, name, args)
+		if !errors.Is(err, gameclient.ErrNotSent) {
+			return res, err
 		}
 	}
-	return nil
+	out, err := a.api.PostCommand(ctx, name, args)
+	if err != nil {
+		return out.Result, err
+	}
+	a.state.SetVM(out.ViewModel)
+	return out.Result, nil
+}
+
+// pollExplain 在滑鼠停留於 Est. Success 時背景取得拆解資料。
+func (a *App) pollExplain() {
+	if time.Since(a.lastExplain) < a.explainPoll || a.explainBusy.Load() {
+		return
+	}
+	a.explainBusy.Store(true)
+	a.lastExplain = time.Now()
+	go func() {
+		defer a.explainBusy.Store(false)
+		ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+		defer cancel()
+		e, err := a.api.GetExplain(ctx, "")
+		if err != nil {
+			a.reportErr(err)
+			return
+		}
+		a.state.SetExplain(e)
+	}()
+}
+
+// pollHistory 在統計面板開啟時背景取得時間序列（不佔用 busy，避免阻擋一般操作）。
+func (a *App) pollHistory() {
+	if time.Since(a.lastHistory) < a.historyPoll || a.histBusy.Load() {
+		return
+	}
+	a.histBusy.Store(true)
+	a.lastHistory = time.Now()
+	res := a.statsRes
+	go func() {
+		defer a.histBusy.Store(false)
+		ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+		defer cancel()
+		h, err := a.api.GetHistory(ctx, res)
+		if err != nil {
+			a.reportErr(err)
+			return
+		}
+		a.state.SetHistory(h)
+	}()
+}
+
+// ruleError 吞掉伺服器的規則拒絕（409/422）：原因已由伺服器記為通知並隨 ViewModel 顯示；其他錯誤原樣回傳。
+func (a *App) ruleError(err error) error {
+	if gameclient.IsRuleViolation(err) {
+		return nil
+	}
+	return err
+}
+
+// authRetry 為 token 被拒（401）後的重試間隔：token 檔不會自行改變，避免每幀重送請求與洗版錯誤。
+const authRetry = 5 * time.Second
+
+// reportErr 顯示錯誤；401 時改以固定提示說明如何處理，並讓輪詢與重連放慢到 authRetry。
+func (a *App) reportErr(err error) {
+	if !gameclient.IsUnauthorized(err) {
+		a.state.SetErr(err.Error())
+		return
+	}
+	a.unauthorized.Store(true)
+	a.state.SetErr("API token rejected: restart the server or check " + a.api.TokenFile())
 }
 
 func (a *App) trigger(fn func(ctx context.Context) error) {
@@ -435,10 +599,11 @@ func (a *App) trigger(fn func(ctx context.Context) error) {
 	a.netShowSince = time.Now()
 	go func() {
 		defer a.busy.Store(false)
-		ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+		// 每個動作一個 Idempotency-Key：動作內的重送（含 transport 的自動重送）會被伺服器辨識為同一請求
+		ctx, cancel := context.WithTimeout(gameclient.WithIdempotencyKey(context.Background(), gameclient.NewIdempotencyKey()), 1500*time.Millisecond)
 		defer cancel()
 		if err := fn(ctx); err != nil {
-			a.state.SetErr(err.Error())
+			a.reportErr(err)
 		} else {
 			a.state.SetErr("")
 		}
@@ -496,6 +661,39 @@ func (a *App) Draw(screen *ebiten.Image) {
 	} else if vm.GPUs < a.prevGPUs {
 		a.prevGPUs = vm.GPUs
 	}
+	// 偵測伺服器端結算的任務（任務消失或換成另一個）：依知識增減播放戰鬥動畫
+	taskKey := ""
+	if vm.CurrentTask != nil {
+		taskKey = vm.CurrentTask.ID + "|" + vm.CurrentTask.EndsAt
+	}
+	if taskKey != a.prevTaskKey {
+		if a.prevTaskKey != "" {
+			lang := a.prevTaskLang
+			if lang == "" {
+				lang = vm.CurrentLanguage
+			}
+			a.battleLang = lang
+			r := rand.New(rand.NewSource(nowAnim.UnixNano()))
+			a.battleStyle = 1 + r.Intn(3)
+			if gained := vm.Languages[lang].Knowledge - a.prevTaskK; gained > 0 {
+				a.battleType = "success"
+				a.floatText = fmt.Sprintf("+%d K", gained)
+				a.floatStart = nowAnim
+				a.floatUntil = a.floatStart.Add(800 * time.Millisecond)
+			} else {
+				a.battleType = "fail"
+			}
+			a.battleUntil = nowAnim.Add(700 * time.Millisecond)
+		}
+		a.prevTaskKey = taskKey
+		a.prevTaskLang = ""
+		if vm.CurrentTask != nil {
+			a.prevTaskLang = vm.CurrentTask.Language
+		}
+	}
+	if a.prevTaskKey != "" {
+		a.prevTaskK = vm.Languages[a.prevTaskLang].Knowledge
+	}
 	// 轉成 HUD VM
 	hudVM := VM{
 		Knowledge:        vm.Knowledge,
@@ -605,15 +803,16 @@ func (a *App) Draw(screen *ebiten.Image) {
 	// 取得 toast 訊息
 	toast := a.getToast()
 	DrawHUD(screen, a.face, hudVM, errMsg, showNetworking)
+	// 在左側卡片下方顯示本地 toast 與伺服器通知
+	// 位置：靠近卡片底部稍微往下
+	noticeX := Theme.Pad8 * 2
+	noticeY := Theme.Pad8*2 + 320 + Theme.Pad8 + 92 + Theme.Pad8 + 12 // Status 卡 + Task 卡 + padding
 	if toast != "" {
-		// 在左側卡片下方顯示一條短提示
-		// 這裡重用 HUD 的樣式：以 Good 色字顯示（或 Warn 也可）
-		// 位置：靠近卡片底部稍微往下
-		x := Theme.Pad8 * 2
-		y := Theme.Pad8*2 + 320 + Theme.Pad8 + 92 + Theme.Pad8 + 12 // Status 卡 + Task 卡 + padding
 		// 使用 HUD 的封裝繪字（含 baseline 調整）
-		drawText(screen, a.face, toast, x, y, Theme.Good)
+		drawText(screen, a.face, toast, noticeX, noticeY, Theme.Good)
+		noticeY += 18
 	}
+	DrawNotices(screen, a.face, vm.Notices, noticeX, noticeY)
 
 	// 浮出 +Knowledge 數字（位於畫面中央，往上飄並淡出）
 	if a.floatText != "" && !a.floatUntil.IsZero() {
@@ -643,6 +842,16 @@ func (a *App) Draw(screen *ebiten.Image) {
 	// 目前移除右側 AI 顯示（暫時不影響遊戲設計）
 	// 若日後恢復，請在此重新計算位置並呼叫 DrawEvolvingAI
 
+	if a.hoverExplain {
+		mx, my := ebiten.CursorPosition()
+		DrawExplainTooltip(screen, a.face, a.state.ExplainSnapshot(), mx, my)
+	}
+
+	// Stats panel overlay
+	if a.showStats {
+		DrawStatsPanel(screen, a.face, a.state.HistorySnapshot())
+	}
+
 	// Tutorial overlay
 	if a.showTutorial {
 		sw, sh := screen.Size()
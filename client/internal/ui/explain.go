@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+
+	"go-ddd-architecture/client/internal/api/gameclient"
+)
+
+// DrawExplainTooltip 於滑鼠附近顯示各任務類型的成功率、時長與獎勵拆解。
+func DrawExplainTooltip(screen *ebiten.Image, face font.Face, e gameclient.Explain, mx, my int) {
+	if len(e.Tasks) == 0 {
+		return
+	}
+	lines := []string{"Why these odds? (" + e.Language + ")"}
+	// 成功率組成與任務類型無關，取第一項列出
+	var parts []string
+	for _, f := range e.Tasks[0].SuccessFactors {
+		parts = append(parts, fmt.Sprintf("%s %+.0f%%", f.Name, f.Value*100))
+	}
+	lines = append(lines, "Success = "+strings.Join(parts, ", "))
+	for _, t := range e.Tasks {
+		lines = append(lines, fmt.Sprintf("%-8s %3.0f%%  %.1fs (base %.0fs)  +%dK  R %d..%d  ~%.1fK",
+			t.Type, t.Success*100, t.DurationSeconds, t.BaseDurationSeconds, t.Reward, t.ResearchMin, t.ResearchMax, t.ExpectedKnowledge))
+	}
+	for _, f := range e.Tasks[0].RewardFactors {
+		lines = append(lines, "  "+f.Detail)
+	}
+
+	pad := Theme.Pad8
+	w := 0
+	for _, l := range lines {
+		w = max(w, textWidth(face, l))
+	}
+	w += pad * 2
+	h := len(lines)*18 + pad*2
+	sw, sh := screen.Bounds().Dx(), screen.Bounds().Dy()
+	x := min(mx+16, sw-w-4)
+	y := min(my+16, sh-h-4)
+	drawCard(screen, x, y, w, h)
+	drawRoundedRectOutline(screen, x, y, w, h, Theme.Radius8, Theme.OutlineBlue, 1)
+	ly := y + pad + 12
+	for i, l := range lines {
+		col := Theme.TextSub
+		if i == 0 {
+			col = Theme.TextMain
+		}
+		drawText(screen, face, l, x+pad, ly, col)
+		ly += 18
+	}
+}
@@ -23,12 +23,19 @@ type uiRect struct{ X, Y, W, H int }
 var (
 	lastServerBuyRect uiRect
 	lastGPUBuyRect    uiRect
+	// Est. Success 文字區域（滑鼠停留時顯示拆解 tooltip）
+	lastSuccessRect uiRect
 )
 
 // 防止單檔靜態檢查誤判未使用（跨檔案會使用）
 func init() {
 	_ = lastServerBuyRect
 	_ = lastGPUBuyRect
+	_ = lastSuccessRect
+}
+
+func (r uiRect) contains(x, y int) bool {
+	return x >= r.X && y >= r.Y && x < r.X+r.W && y < r.Y+r.H
 }
 
 // DrawHUD draws the main HUD: resources, level, current task timer, etc.
@@ -132,8 +139,12 @@ func DrawHUD(screen *ebiten.Image, face font.Face, vm VM, errMsg string, inFligh
 	drawText(screen, face, fmt.Sprintf("HW bonus R/min: +%d (GPUs %d x %d)", bonus, vm.GPUs, GPUBonusRPM), tx, ty, Theme.TextSub)
 	ty += 18
 	if vm.EstimatedSuccess > 0 {
-		drawText(screen, face, fmt.Sprintf("Est. Success: %.0f%%", vm.EstimatedSuccess*100), tx, ty, Theme.TextSub)
+		label := fmt.Sprintf("Est. Success: %.0f%% (?)", vm.EstimatedSuccess*100)
+		drawText(screen, face, label, tx, ty, Theme.TextSub)
+		lastSuccessRect = uiRect{X: tx, Y: ty - 14, W: textWidth(face, label), H: 18}
 		ty += 22
+	} else {
+		lastSuccessRect = uiRect{}
 	}
 
 	// Networking / Error in left card bottom area
@@ -579,7 +590,7 @@ func DrawHUD(screen *ebiten.Image, face font.Face, vm VM, errMsg string, inFligh
 	// 底部極簡 Hotkeys（僅必要鍵，並尊重 ShowHotkeys）
 	if vm.ShowHotkeys {
 		// 幾個層級，依螢幕寬度自適應
-		full := "P Practice  T Targeted  D Deploy  R Research  U Upgrade  C Claim  1 Go  2 Py  3 JS"
+		full := "P Practice  T Targeted  D Deploy  R Research  U Upgrade  C Claim  X Dismiss  A Auto  1 Go  2 Py  3 JS"
 		mid := "P T D R U C  |  1 Go 2 Py 3 JS"
 		small := "P T D R U C | 1 2 3"
 		// 選擇可容納的字串
@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+
+	"go-ddd-architecture/client/internal/api/gameclient"
+)
+
+// maxVisibleNotices 為畫面上同時顯示的通知數（其餘以「+N more」表示）。
+const maxVisibleNotices = 3
+
+// DrawNotices 於 (x, y) 起逐行顯示伺服器通知（最新在前），依嚴重程度著色。
+// X 確認最新一則、Shift+X 全部確認。
+func DrawNotices(screen *ebiten.Image, face font.Face, notices []gameclient.Notice, x, y int) {
+	for i, n := range notices {
+		if i == maxVisibleNotices {
+			drawText(screen, face, fmt.Sprintf("+%d more (Shift+X dismiss all)", len(notices)-i), x, y, Theme.TextSub)
+			return
+		}
+		msg := n.Message
+		if n.Count > 1 {
+			msg = fmt.Sprintf("%s (x%d)", msg, n.Count)
+		}
+		if i == 0 {
+			msg += "  [X]"
+		}
+		drawText(screen, face, msg, x, y, noticeColor(n.Severity))
+		y += 18
+	}
+}
+
+func noticeColor(severity string) color.RGBA {
+	switch severity {
+	case "error":
+		return Theme.Error
+	case "warning":
+		return Theme.Warn
+	}
+	return Theme.Good
+}
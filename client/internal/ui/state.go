@@ -2,6 +2,7 @@ package ui
 
 import (
 	"sync"
+	"time"
 
 	"go-ddd-architecture/client/internal/api/gameclient"
 )
@@ -11,14 +12,23 @@ type State struct {
 	mu sync.RWMutex
 
 	VM gameclient.ViewModel
+	// vmAt 為收到 VM 的時間；推播只在狀態變更時送達，剩餘秒數於讀取時依經過時間遞減
+	vmAt time.Time
 
 	// 錯誤訊息（例如 API 失敗）
 	Err string
+
+	// 統計面板使用的時間序列（非輪詢，開啟面板時才更新）
+	History gameclient.History
+
+	// 成功率/獎勵拆解（滑鼠停在 Est. Success 上時更新）
+	Explain gameclient.Explain
 }
 
 func (s *State) SetVM(vm gameclient.ViewModel) {
 	s.mu.Lock()
 	s.VM = vm
+	s.vmAt = time.Now()
 	s.mu.Unlock()
 }
 
@@ -32,6 +42,39 @@ func (s *State) Snapshot() (vm gameclient.ViewModel, errMsg string) {
 	s.mu.RLock()
 	vm = s.VM
 	errMsg = s.Err
+	at := s.vmAt
 	s.mu.RUnlock()
+	if vm.CurrentTask != nil && !at.IsZero() {
+		t := *vm.CurrentTask
+		t.RemainingSeconds -= int64(time.Since(at) / time.Second)
+		if t.RemainingSeconds < 0 {
+			t.RemainingSeconds = 0
+		}
+		vm.CurrentTask = &t
+	}
 	return
 }
+
+func (s *State) SetHistory(h gameclient.History) {
+	s.mu.Lock()
+	s.History = h
+	s.mu.Unlock()
+}
+
+func (s *State) HistorySnapshot() gameclient.History {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.History
+}
+
+func (s *State) SetExplain(e gameclient.Explain) {
+	s.mu.Lock()
+	s.Explain = e
+	s.mu.Unlock()
+}
+
+func (s *State) ExplainSnapshot() gameclient.Explain {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Explain
+}
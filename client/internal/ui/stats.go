@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/image/font"
+
+	"go-ddd-architecture/client/internal/api/gameclient"
+)
+
+// chartSeries 為折線圖的一條序列。
+type chartSeries struct {
+	Label  string
+	Color  color.RGBA
+	Values []float64
+}
+
+// DrawStatsPanel 以 2x2 折線圖顯示 Knowledge/Research/Level（各語言）與硬體數量的歷史。
+func DrawStatsPanel(screen *ebiten.Image, face font.Face, h gameclient.History) {
+	sw, sh := screen.Bounds().Dx(), screen.Bounds().Dy()
+	overlay := color.RGBA{0, 0, 0, 160}
+	vector.DrawFilledRect(screen, 0, 0, float32(sw), float32(sh), overlay, true)
+
+	pad := Theme.Pad8
+	w := min(sw-80, 860)
+	ht := min(sh-60, 460)
+	x := (sw - w) / 2
+	y := (sh - ht) / 2
+	drawCard(screen, x, y, w, ht)
+	drawRoundedRectOutline(screen, x, y, w, ht, Theme.Radius8, Theme.OutlineBlue, 1)
+
+	title := fmt.Sprintf("Stats (%s, %d points)", h.Resolution, len(h.Points))
+	drawText(screen, face, title, x+pad*2, y+pad*2+12, Theme.TextMain)
+	hint := "S close · Tab resolution"
+	drawText(screen, face, hint, x+w-textWidth(face, hint)-pad*2, y+pad*2+12, Theme.TextSub)
+
+	if len(h.Points) == 0 {
+		msg := "No samples yet"
+		drawText(screen, face, msg, x+(w-textWidth(face, msg))/2, y+ht/2, Theme.TextSub)
+		return
+	}
+
+	langs := historyLanguages(h)
+	pick := func(f func(gameclient.LangVM) float64) []chartSeries {
+		out := make([]chartSeries, 0, len(langs))
+		for _, code := range langs {
+			vals := make([]float64, len(h.Points))
+			for i, p := range h.Points {
+				vals[i] = f(p.Languages[code])
+			}
+			out = append(out, chartSeries{Label: code, Color: paletteForLanguage(code).Node, Values: vals})
+		}
+		return out
+	}
+	hw := func(f func(gameclient.HistoryPoint) int) []float64 {
+		vals := make([]float64, len(h.Points))
+		for i, p := range h.Points {
+			vals[i] = float64(f(p))
+		}
+		return vals
+	}
+
+	top := y + pad*2 + 24
+	cw := (w - pad*6) / 2
+	ch := (ht - (top - y) - pad*4) / 2
+	c1x, c2x := x+pad*2, x+pad*4+cw
+	r1y, r2y := top, top+ch+pad*2
+	drawLineChart(screen, face, c1x, r1y, cw, ch, "Knowledge", pick(func(l gameclient.LangVM) float64 { return float64(l.Knowledge) }))
+	drawLineChart(screen, face, c2x, r1y, cw, ch, "Research", pick(func(l gameclient.LangVM) float64 { return float64(l.Research) }))
+	drawLineChart(screen, face, c1x, r2y, cw, ch, "Level", pick(func(l gameclient.LangVM) float64 { return float64(l.Level) }))
+	drawLineChart(screen, face, c2x, r2y, cw, ch, "Hardware", []chartSeries{
+		{Label: "Servers", Color: Theme.Warn, Values: hw(func(p gameclient.HistoryPoint) int { return p.Servers })},
+		{Label: "GPUs", Color: Theme.Good, Values: hw(func(p gameclient.HistoryPoint) int { return p.GPUs })},
+	})
+}
+
+// drawLineChart 繪製單張折線圖：標題、最大值刻度、圖例與各序列折線（y 軸自 0 起算）。
+func drawLineChart(dst *ebiten.Image, face font.Face, x, y, w, h int, title string, series []chartSeries) {
+	drawRoundedFilledRect(dst, x, y, w, h, 6, Theme.Bg)
+	drawRoundedRectOutline(dst, x, y, w, h, 6, Theme.CardBorder, 1)
+	drawText(dst, face, title, x+6, y+16, Theme.TextMain)
+
+	maxV := 0.0
+	for _, s := range series {
+		for _, v := range s.Values {
+			if v > maxV {
+				maxV = v
+			}
+		}
+	}
+	if maxV <= 0 {
+		maxV = 1
+	}
+	scale := fmt.Sprintf("%.0f", maxV)
+	drawText(dst, face, scale, x+w-textWidth(face, scale)-6, y+16, Theme.TextSub)
+
+	// 圖例
+	lx := x + 6 + textWidth(face, title) + 12
+	for _, s := range series {
+		vector.DrawFilledRect(dst, float32(lx), float32(y+8), 8, 8, s.Color, true)
+		drawText(dst, face, s.Label, lx+12, y+16, Theme.TextSub)
+		lx += 12 + textWidth(face, s.Label) + 10
+	}
+
+	px, py, pw, ph := x+6, y+24, w-12, h-30
+	if pw <= 0 || ph <= 0 {
+		return
+	}
+	vector.StrokeLine(dst, float32(px), float32(py+ph), float32(px+pw), float32(py+ph), 1, Theme.CardBorder, true)
+	for _, s := range series {
+		n := len(s.Values)
+		if n == 0 {
+			continue
+		}
+		pt := func(i int) (float32, float32) {
+			fx := float32(px)
+			if n > 1 {
+				fx += float32(pw) * float32(i) / float32(n-1)
+			}
+			fy := float32(py+ph) - float32(ph)*float32(s.Values[i]/maxV)
+			return fx, fy
+		}
+		if n == 1 {
+			fx, fy := pt(0)
+			vector.DrawFilledCircle(dst, fx, fy, 2, s.Color, true)
+			continue
+		}
+		x0, y0 := pt(0)
+		for i := 1; i < n; i++ {
+			x1, y1 := pt(i)
+			vector.StrokeLine(dst, x0, y0, x1, y1, 1.5, s.Color, true)
+			x0, y0 = x1, y1
+		}
+	}
+}
+
+// historyLanguages 回傳時間序列中出現過的語言（go/py/js 優先，其餘依字母）。
+func historyLanguages(h gameclient.History) []string {
+	seen := map[string]bool{}
+	for _, p := range h.Points {
+		for code := range p.Languages {
+			seen[code] = true
+		}
+	}
+	var out []string
+	for _, code := range []string{"go", "py", "js"} {
+		if seen[code] {
+			out = append(out, code)
+			delete(seen, code)
+		}
+	}
+	var rest []string
+	for code := range seen {
+		rest = append(rest, code)
+	}
+	sort.Strings(rest)
+	return append(out, rest...)
+}
@@ -40,14 +40,12 @@ var offlineCmd = &cobra.Command{
 			uc = game.NewInteractor(store, clk, calc)
 		}
 
+		// Initialize 會自動結算離線收益
 		if err := uc.Initialize(); err != nil {
 			return err
 		}
 
-		res, err := uc.ClaimOffline(clk.Now())
-		if err != nil {
-			return err
-		}
+		res := uc.InitialOffline()
 		vm := uc.GetViewModel()
 		fmt.Printf("Offline Result: %+v\n", res)
 		fmt.Printf("ViewModel: Knowledge=%d Research=%d\n", vm.Knowledge, vm.Research)
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/fx"
@@ -14,14 +16,22 @@ import (
 	"go-ddd-architecture/app/infra/clock"
 	"go-ddd-architecture/app/infra/memory"
 	bb "go-ddd-architecture/app/infra/persistence/bbolt"
+	"go-ddd-architecture/app/infra/persistence/eventlog"
+	mongoStore "go-ddd-architecture/app/infra/persistence/mongo"
+	"go-ddd-architecture/app/infra/telemetry"
 	"go-ddd-architecture/app/usecase/game"
 	outPort "go-ddd-architecture/app/usecase/port/out/game"
+	"go-ddd-architecture/internal/apitoken"
+	"go-ddd-architecture/internal/metrics"
 )
 
 // serverCmd -  represents the server command
 var serverCmd = &cobra.Command{
 	Use:   "server",
 	Short: "start server",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return validateServerFlags()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		server()
 	},
@@ -30,12 +40,49 @@ var serverCmd = &cobra.Command{
 var (
 	flagServerDBPath    string
 	flagServerUseMemory bool
+	flagServerEventLog  string
+	flagServerSampleInt time.Duration
+	flagServerMongoURI  string
+	flagServerMongoDB   string
+	flagServerProfile   string
+	flagServerHeartbeat time.Duration
+	flagServerDev       bool
+	flagServerTimeScale float64
+	flagServerTimeShift time.Duration
+	flagServerSunset    string
+	flagServerAuth      bool
+	flagServerTokenFile string
+	flagServerRateLimit bool
 )
 
 func init() {
 	rootCmd.AddCommand(serverCmd)
 	serverCmd.Flags().StringVar(&flagServerDBPath, "db", "game.db", "path to bbolt db file")
 	serverCmd.Flags().BoolVar(&flagServerUseMemory, "mem", true, "use in-memory repository (no persistence)")
+	serverCmd.Flags().DurationVar(&flagServerHeartbeat, "heartbeat", 30*time.Second, "interval for persisting the close time while running")
+	serverCmd.Flags().DurationVar(&flagServerSampleInt, "sample-interval", time.Minute, "interval between stat history samples")
+	serverCmd.Flags().StringVar(&flagServerEventLog, "eventlog", "", "path to append-only event log file (overrides --mem/--db; cannot be combined with --mongo)")
+	serverCmd.Flags().StringVar(&flagServerMongoURI, "mongo", "", "MongoDB-compatible URI, or memory:// for the in-process fake (overrides --mem/--db)")
+	serverCmd.Flags().StringVar(&flagServerMongoDB, "mongo-db", "idlegame", "database name used with --mongo")
+	serverCmd.Flags().StringVar(&flagServerProfile, "profile", mongoStore.DefaultProfile, "save profile name used with --mongo")
+	serverCmd.Flags().BoolVar(&flagServerDev, "dev", false, "dev mode: controllable game clock and /api/v1/dev/clock endpoints")
+	serverCmd.Flags().Float64Var(&flagServerTimeScale, "time-scale", 1, "game clock speed in dev mode (e.g. 10 for 10x)")
+	serverCmd.Flags().StringVar(&flagServerSunset, "legacy-sunset", "", "RFC3339 time after which the deprecated /api/game prefix returns 410 (announced via the Sunset header)")
+	serverCmd.Flags().DurationVar(&flagServerTimeShift, "time-offset", 0, "shift the game clock from system time in dev mode (e.g. 10h)")
+	serverCmd.Flags().BoolVar(&flagServerAuth, "auth", true, "require the local API token (Authorization: Bearer) on every request")
+	serverCmd.Flags().BoolVar(&flagServerRateLimit, "rate-limit", true, "limit request rate per client (token or IP); 429 with Retry-After when exceeded")
+	serverCmd.Flags().StringVar(&flagServerTokenFile, "token-file", "", "API token file, generated on first start (default: <user config dir>/intellect/api-token.json)")
+}
+
+// validateServerFlags 於啟動前檢查旗標，避免無效值在元件啟動後才使 time.NewTicker 等 panic。
+func validateServerFlags() error {
+	if flagServerSampleInt <= 0 {
+		return fmt.Errorf("--sample-interval must be positive, got %s", flagServerSampleInt)
+	}
+	if flagServerHeartbeat <= 0 {
+		return fmt.Errorf("--heartbeat must be positive, got %s", flagServerHeartbeat)
+	}
+	return nil
 }
 
 // server -
@@ -47,10 +94,55 @@ func server() {
 		fx.Provide(
 			// logger
 			func() (*zap.Logger, error) { return zap.NewDevelopment() },
-			func() clock.SystemClock { return clock.SystemClock{} },
+			// 時鐘：開發模式使用可控制的時鐘（偏移/加速），否則為系統時鐘；所有時間讀取皆經由 outPort.Clock
+			func() (*clock.ControlClock, error) {
+				if !flagServerDev {
+					return nil, nil
+				}
+				cc := clock.NewControlClock()
+				cc.Advance(flagServerTimeShift)
+				if err := cc.SetScale(flagServerTimeScale); err != nil {
+					return nil, err
+				}
+				return cc, nil
+			},
+			func(cc *clock.ControlClock) outPort.Clock {
+				if cc != nil {
+					return cc
+				}
+				return clock.SystemClock{}
+			},
 			func() *gametime.OfflineCalculator { return gametime.NewOfflineCalculator() },
-			// Repository：依旗標切換 memory 或 bbolt
-			func() (outPort.Repository, error) {
+			// Repository：依旗標切換 event log、mongo、memory 或 bbolt
+			func(lc fx.Lifecycle, log *zap.Logger) (outPort.Repository, error) {
+				if flagServerMongoURI != "" && flagServerEventLog != "" {
+					return nil, fmt.Errorf("--mongo and --eventlog cannot be used together")
+				}
+				if flagServerMongoURI != "" {
+					ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+					defer cancel()
+					drv, err := mongoStore.Connect(ctx, flagServerMongoURI, flagServerMongoDB)
+					if err != nil {
+						return nil, err
+					}
+					repo := mongoStore.New(drv, flagServerProfile)
+					lc.Append(fx.Hook{OnStop: func(ctx context.Context) error { return drv.Close(ctx) }})
+					return repo, nil
+				}
+				if flagServerEventLog != "" {
+					store, err := eventlog.New(flagServerEventLog)
+					if err != nil {
+						return nil, err
+					}
+					if n := store.Truncated(); n > 0 {
+						log.Warn("event log had a torn final entry; truncated", zap.String("file", flagServerEventLog), zap.Int64("bytes", n))
+					}
+					store.SetMaintenanceErrorHandler(func(err error) {
+						log.Warn("event log compaction/checkpoint failed; the save itself succeeded", zap.String("file", flagServerEventLog), zap.Error(err))
+					})
+					lc.Append(fx.Hook{OnStop: func(context.Context) error { return store.Close() }})
+					return store, nil
+				}
 				if flagServerUseMemory {
 					return memory.NewInMemoryRepo(), nil
 				}
@@ -58,22 +150,92 @@ func server() {
 				if err != nil {
 					return nil, err
 				}
+				lc.Append(fx.Hook{OnStop: func(context.Context) error { return store.Close() }})
 				return store, nil
 			},
-			func(clk clock.SystemClock, calc *gametime.OfflineCalculator, repo outPort.Repository) *game.Interactor {
-				return game.NewInteractor(repo, clk, calc)
+			// HistoryStore：Repository 若支援（bbolt）則共用同一檔案，否則退回記憶體
+			func(repo outPort.Repository) outPort.HistoryStore {
+				if hs, ok := repo.(outPort.HistoryStore); ok {
+					return hs
+				}
+				return memory.NewInMemoryHistory()
+			},
+			// metrics：/metrics 輸出 HTTP、存檔延遲、任務與遊戲資源指標
+			func() *metrics.Registry { return metrics.NewRegistry() },
+			// 用例使用記錄延遲的 Repository；HistoryStore 的型別判斷仍以原實作為準
+			func(clk outPort.Clock, calc *gametime.OfflineCalculator, repo outPort.Repository, reg *metrics.Registry) *game.Interactor {
+				uc := game.NewInteractor(telemetry.WrapRepository(repo, reg), clk, calc)
+				uc.SetTaskObserver(telemetry.NewTaskMetrics(reg))
+				telemetry.RegisterEconomyGauges(reg, uc.GetViewModel)
+				return uc
+			},
+			func(uc *game.Interactor, hs outPort.HistoryStore, clk outPort.Clock) *game.HistoryService {
+				return game.NewHistoryService(uc, hs, clk)
 			},
 			// HTTP adapter
 			// game 模組 handler/router
-			func(uc *game.Interactor, log *zap.Logger) *httpGame.Handler { return httpGame.NewHandler(uc, log) },
-			func(h *httpGame.Handler) *httpGame.Router { return httpGame.NewRouter(h) },
+			func(uc *game.Interactor, hist *game.HistoryService, clk outPort.Clock, log *zap.Logger) *httpGame.Handler {
+				return httpGame.NewHandler(uc, hist, clk, log)
+			},
+			func(uc *game.Interactor, cc *clock.ControlClock) *httpGame.DevHandler {
+				if cc == nil {
+					return nil
+				}
+				return httpGame.NewDevHandler(uc, cc)
+			},
+			func(h *httpGame.Handler, dev *httpGame.DevHandler) (*httpGame.Router, error) {
+				// 停用時間以真實時間判斷，不受開發模式的遊戲時鐘影響
+				opts := httpGame.RouterOptions{WallClock: clock.SystemClock{}}
+				if flagServerSunset != "" {
+					t, err := time.Parse(time.RFC3339, flagServerSunset)
+					if err != nil {
+						return nil, fmt.Errorf("invalid --legacy-sunset: %w", err)
+					}
+					opts.LegacySunset = t
+				}
+				return httpGame.NewRouter(h, dev, opts), nil
+			},
 			// 聚合 router
-			func(gr *httpGame.Router) *httpserver.Router { return httpserver.NewRouter(gr) },
-			func(r *httpserver.Router, log *zap.Logger) *httpserver.Server {
-				return httpserver.NewServer("127.0.0.1:8080", r.HandlerWithLogger(log))
+			func(gr *httpGame.Router, reg *metrics.Registry) *httpserver.Router {
+				return httpserver.NewRouter(gr, reg)
+			},
+			// API token：首次啟動產生並寫入使用者設定目錄（gameclient 自動讀取）；--auth=false 時不驗證
+			func(log *zap.Logger) (*apitoken.Tokens, error) {
+				if !flagServerAuth {
+					log.Warn("API authentication disabled (--auth=false)")
+					return nil, nil
+				}
+				path := flagServerTokenFile
+				if path == "" {
+					p, err := apitoken.DefaultPath()
+					if err != nil {
+						return nil, fmt.Errorf("locate token file: %w", err)
+					}
+					path = p
+				}
+				tokens, created, err := apitoken.LoadOrCreate(path)
+				if err != nil {
+					return nil, fmt.Errorf("load token file: %w", err)
+				}
+				log.Info("API token loaded", zap.String("file", path), zap.Bool("created", created))
+				return &tokens, nil
+			},
+			func(r *httpserver.Router, tokens *apitoken.Tokens, log *zap.Logger) *httpserver.Server {
+				var mws []httpserver.Middleware
+				if tokens != nil {
+					mws = append(mws, httpserver.NewAuthMiddleware(*tokens, httpGame.PathOpenAPI))
+				}
+				// 速率限制接在驗證之後，才能以 token 區分客戶端
+				if flagServerRateLimit {
+					mws = append(mws, httpserver.NewRateLimitMiddleware(log, httpserver.DefaultRateLimits()))
+				}
+				// Idempotency-Key：客戶端逾時重試時重播原回應，避免重複購買等
+				mws = append(mws, httpserver.NewIdempotencyMiddleware(httpserver.DefaultIdempotencyTTL, httpserver.DefaultIdempotencyMaxEntries))
+				return httpserver.NewServer("127.0.0.1:8080", r.HandlerWithLogger(log, mws...))
 			},
 		),
-		fx.Invoke(NewGRPCServer, StartHTTPServer, InitUsecase),
+		// InitUsecase 需在 HTTP 之前註冊：啟動時先載入存檔，關閉時在 HTTP 停止後才記錄關閉時間
+		fx.Invoke(NewGRPCServer, InitUsecase, StartHTTPServer, StartStatsSampler, StartScheduler),
 	)
 
 	if err := app.Err(); err != nil {
@@ -109,10 +271,99 @@ func StartHTTPServer(lc fx.Lifecycle, srv *httpserver.Server) error {
 	return nil
 }
 
-// InitUsecase 在啟動時載入資料到 Interactor，避免初次請求時使用零值 timestamps。
-func InitUsecase(lc fx.Lifecycle, uc *game.Interactor) error {
+// InitUsecase 在啟動時載入資料並自動結算離線收益；執行期間以心跳持久化關閉時間，
+// 關閉時再記錄一次，確保下次啟動的離線時間反映實際關閉的時間點。
+func InitUsecase(lc fx.Lifecycle, uc *game.Interactor, clk outPort.Clock, log *zap.Logger) error {
+	stop := make(chan struct{})
+	done := make(chan struct{})
 	lc.Append(fx.Hook{
-		OnStart: func(ctx context.Context) error { return uc.Initialize() },
+		OnStart: func(ctx context.Context) error {
+			if err := uc.Initialize(); err != nil {
+				return err
+			}
+			res := uc.InitialOffline()
+			log.Info("offline settled",
+				zap.Int64("knowledge", res.GainedKnowledge),
+				zap.Int64("research", res.GainedResearch),
+				zap.Bool("clamped", res.ClampedTo8h),
+				zap.Bool("anomaly", res.AnomalyDetected),
+			)
+			go func() {
+				defer close(done)
+				t := time.NewTicker(flagServerHeartbeat)
+				defer t.Stop()
+				for {
+					select {
+					case <-t.C:
+						if err := uc.RecordClose(clk.Now()); err != nil {
+							log.Warn("heartbeat failed", zap.Error(err))
+						}
+					case <-stop:
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stop)
+			<-done
+			return uc.RecordClose(clk.Now())
+		},
+	})
+	return nil
+}
+
+// StartStatsSampler 週期性取樣統計資料寫入時間序列（啟動時先取樣一次）。
+func StartStatsSampler(lc fx.Lifecycle, hs *game.HistoryService, log *zap.Logger) error {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				defer close(done)
+				t := time.NewTicker(flagServerSampleInt)
+				defer t.Stop()
+				for {
+					if err := hs.Sample(); err != nil {
+						log.Warn("stats sample failed", zap.Error(err))
+					}
+					select {
+					case <-t.C:
+					case <-stop:
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stop)
+			<-done
+			return nil
+		},
+	})
+	return nil
+}
+
+// StartScheduler 啟動伺服器端自動化排程：於任務完成時間自動結算並接續下一個任務。
+func StartScheduler(lc fx.Lifecycle, uc *game.Interactor, clk outPort.Clock, log *zap.Logger) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	sched := game.NewScheduler(uc, clk)
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				sched.Run(ctx, func(err error) { log.Warn("scheduler advance failed", zap.Error(err)) })
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			<-done
+			return nil
+		},
 	})
 	return nil
 }
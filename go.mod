@@ -8,9 +8,11 @@ require (
 	github.com/hajimehoshi/ebiten/v2 v2.8.8
 	github.com/spf13/cobra v1.8.1
 	go.etcd.io/bbolt v1.4.2
+	go.mongodb.org/mongo-driver/v2 v2.3.0
 	go.uber.org/fx v1.19.3
 	go.uber.org/zap v1.23.0
 	golang.org/x/image v0.30.0
+	golang.org/x/sys v0.30.0
 )
 
 require (
@@ -18,13 +20,19 @@ require (
 	github.com/ebitengine/hideconsole v1.0.0 // indirect
 	github.com/ebitengine/purego v0.8.0 // indirect
 	github.com/go-text/typesetting v0.2.0 // indirect
+	github.com/golang/snappy v1.0.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/jezek/xgb v1.1.1 // indirect
+	github.com/klauspost/compress v1.16.7 // indirect
 	github.com/spf13/pflag v1.0.6 // indirect
+	github.com/xdg-go/pbkdf2 v1.0.0 // indirect
+	github.com/xdg-go/scram v1.1.2 // indirect
+	github.com/xdg-go/stringprep v1.0.4 // indirect
+	github.com/youmark/pkcs8 v0.0.0-20240726163527-a2c0da244d78 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
 	go.uber.org/dig v1.16.1 // indirect
 	go.uber.org/multierr v1.6.0 // indirect
+	golang.org/x/crypto v0.33.0 // indirect
 	golang.org/x/sync v0.16.0 // indirect
-	golang.org/x/sys v0.29.0 // indirect
 	golang.org/x/text v0.28.0 // indirect
 )
@@ -0,0 +1,142 @@
+// Package apitoken 管理本機 API token：伺服器首次啟動時產生並寫入使用者設定目錄，
+// gameclient 自動讀取同一檔案。token 分為唯讀（read）與管理（admin）兩種範圍。
+package apitoken
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Scope 為 token 的權限範圍。
+type Scope string
+
+const (
+	// ScopeRead 只能呼叫查詢（GET/HEAD）與訂閱推送。
+	ScopeRead Scope = "read"
+	// ScopeAdmin 可呼叫所有端點（含狀態變更與開發模式端點）。
+	ScopeAdmin Scope = "admin"
+)
+
+// CanWrite 判斷此範圍是否允許變更狀態。
+func (s Scope) CanWrite() bool { return s == ScopeAdmin }
+
+// Tokens 為 token 檔的內容。
+type Tokens struct {
+	Admin string `json:"admin"`
+	Read  string `json:"read"`
+}
+
+// Lookup 以常數時間比對 token，回傳其範圍；不符時 ok 為 false。
+func (t Tokens) Lookup(token string) (scope Scope, ok bool) {
+	if token == "" {
+		return "", false
+	}
+	if t.Admin != "" && subtle.ConstantTimeCompare([]byte(token), []byte(t.Admin)) == 1 {
+		return ScopeAdmin, true
+	}
+	if t.Read != "" && subtle.ConstantTimeCompare([]byte(token), []byte(t.Read)) == 1 {
+		return ScopeRead, true
+	}
+	return "", false
+}
+
+// DefaultPath 回傳 token 檔的預設位置（使用者設定目錄下，例如 ~/.config/intellect/api-token.json）。
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "intellect", "api-token.json"), nil
+}
+
+// Load 讀取 token 檔；檔案不存在時回傳 fs.ErrNotExist。
+func Load(path string) (Tokens, error) {
+	var t Tokens
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return t, err
+	}
+	if err := json.Unmarshal(b, &t); err != nil {
+		return t, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if t.Admin == "" {
+		return t, fmt.Errorf("parse %s: missing admin token", path)
+	}
+	return t, nil
+}
+
+// LoadOrCreate 讀取 token 檔；不存在時產生新的隨機 token 並寫入（目錄 0700、檔案 0600）。
+// created 表示此次新建了檔案。
+func LoadOrCreate(path string) (t Tokens, created bool, err error) {
+	t, err = Load(path)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return t, false, err
+	}
+	if t.Admin, err = random(); err != nil {
+		return t, false, err
+	}
+	if t.Read, err = random(); err != nil {
+		return t, false, err
+	}
+	if err := write(path, t); err != nil {
+		return t, false, err
+	}
+	return t, true, nil
+}
+
+// write 先寫入暫存檔再改名，避免客戶端讀到寫了一半的檔案。
+func write(path string, t Tokens) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".api-token-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func random() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+type ctxKey struct{}
+
+// WithScope 將已驗證的範圍放入 context（由驗證 middleware 設定）。
+func WithScope(ctx context.Context, s Scope) context.Context {
+	return context.WithValue(ctx, ctxKey{}, s)
+}
+
+// ScopeFrom 取出 context 中的範圍；未啟用驗證時 ok 為 false（視為不限制）。
+func ScopeFrom(ctx context.Context) (Scope, bool) {
+	s, ok := ctx.Value(ctxKey{}).(Scope)
+	return s, ok
+}
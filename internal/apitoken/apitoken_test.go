@@ -0,0 +1,49 @@
+package apitoken
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestLoadOrCreate_GeneratesOnceAndReuses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cfg", "api-token.json")
+	first, created, err := LoadOrCreate(path)
+	if err != nil || !created {
+		t.Fatalf("first start: created=%v err=%v", created, err)
+	}
+	if len(first.Admin) != 64 || len(first.Read) != 64 || first.Admin == first.Read {
+		t.Fatalf("tokens should be distinct 32-byte hex strings: %+v", first)
+	}
+	if runtime.GOOS != "windows" {
+		if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o600 {
+			t.Fatalf("token file should be private: %v %v", fi.Mode(), err)
+		}
+	}
+	again, created, err := LoadOrCreate(path)
+	if err != nil || created || again != first {
+		t.Fatalf("second start should reuse the file: created=%v err=%v", created, err)
+	}
+}
+
+func TestTokens_Lookup(t *testing.T) {
+	tk := Tokens{Admin: "a", Read: "r"}
+	for _, c := range []struct {
+		token string
+		scope Scope
+		ok    bool
+	}{
+		{"a", ScopeAdmin, true},
+		{"r", ScopeRead, true},
+		{"x", "", false},
+		{"", "", false},
+	} {
+		if s, ok := tk.Lookup(c.token); s != c.scope || ok != c.ok {
+			t.Errorf("Lookup(%q) = %q %v", c.token, s, ok)
+		}
+	}
+	if ScopeRead.CanWrite() || !ScopeAdmin.CanWrite() {
+		t.Fatal("only admin may write")
+	}
+}
@@ -0,0 +1,356 @@
+// Package metrics 是不依賴第三方套件的最小 Prometheus 指標實作：
+// 計數器、直方圖與於抓取時計算的量測值（gauge），以 text exposition format 0.0.4 輸出。
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType 為 Prometheus 文字格式的媒體類型。
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefBuckets 為延遲直方圖的預設上界（秒），與 Prometheus 官方客戶端相同。
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// family 為一組同名指標（不同標籤值）。
+type family interface {
+	name() string
+	write(w *bufio.Writer)
+}
+
+// multiFamily 為一次輸出多個指標名稱的 family（GaugeSet）；註冊時逐一檢查名稱。
+type multiFamily interface {
+	names() []string
+}
+
+// Registry 保存所有指標並負責輸出；名稱重複註冊時 panic（屬程式錯誤）。
+type Registry struct {
+	mu       sync.Mutex
+	families []family
+	names    map[string]struct{}
+}
+
+func NewRegistry() *Registry {
+	return &Registry{names: map[string]struct{}{}}
+}
+
+func (r *Registry) register(f family) {
+	names := []string{f.name()}
+	if m, ok := f.(multiFamily); ok {
+		names = m.names()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, n := range names {
+		if _, dup := r.names[n]; dup {
+			panic("metrics: duplicate metric " + n)
+		}
+	}
+	for _, n := range names {
+		r.names[n] = struct{}{}
+	}
+	r.families = append(r.families, f)
+}
+
+// Write 依名稱排序輸出所有指標（GaugeSet 以其第一個名稱排序，組內依名稱連續輸出）。
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	fams := append([]family(nil), r.families...)
+	r.mu.Unlock()
+	sort.Slice(fams, func(i, j int) bool { return fams[i].name() < fams[j].name() })
+	bw := bufio.NewWriter(w)
+	for _, f := range fams {
+		f.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler 回傳輸出所有指標的 HTTP 處理器。
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_ = r.Write(w)
+	})
+}
+
+// desc 為指標的名稱、說明與標籤名稱。
+type desc struct {
+	n, help string
+	labels  []string
+}
+
+func (d desc) name() string { return d.n }
+
+func (d desc) header(w *bufio.Writer, typ string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.n, escapeHelp(d.help), d.n, typ)
+}
+
+// key 將標籤值組成 map 鍵；值數量必須與標籤名稱一致。
+func (d desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", d.n, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// labelPairs 組出 {a="x",b="y"}；extra 為額外的名稱/值（直方圖的 le）。
+func (d desc) labelPairs(values []string, extra ...string) string {
+	if len(d.labels) == 0 && len(extra) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, l := range d.labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(l + `="` + escapeLabel(values[i]) + `"`)
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		b.WriteString(extra[i] + `="` + escapeLabel(extra[i+1]) + `"`)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// CounterVec 為只增不減的計數器。
+type CounterVec struct {
+	desc
+	mu     sync.Mutex
+	values map[string]*counterValue
+}
+
+type counterValue struct {
+	labels []string
+	v      float64
+}
+
+// NewCounter 註冊計數器；labels 為標籤名稱。
+func (r *Registry) NewCounter(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{desc: desc{name, help, labels}, values: map[string]*counterValue{}}
+	r.register(c)
+	return c
+}
+
+// Inc 將指定標籤值的計數加一。
+func (c *CounterVec) Inc(labelValues ...string) { c.Add(1, labelValues...) }
+
+// Add 將指定標籤值的計數加 v（v 需 >= 0）。
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	k := c.key(labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cv, ok := c.values[k]
+	if !ok {
+		cv = &counterValue{labels: append([]string(nil), labelValues...)}
+		c.values[k] = cv
+	}
+	cv.v += v
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.header(w, "counter")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range sortedKeys(c.values) {
+		cv := c.values[k]
+		fmt.Fprintf(w, "%s%s %s\n", c.n, c.labelPairs(cv.labels), formatFloat(cv.v))
+	}
+}
+
+// HistogramVec 為累積直方圖（含 _bucket、_sum、_count）。
+type HistogramVec struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogramValue
+}
+
+type histogramValue struct {
+	labels []string
+	counts []uint64 // 每個上界各自的次數（輸出時累加）
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram 註冊直方圖；buckets 為遞增的上界（不含 +Inf），nil 時使用 DefBuckets。
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	h := &HistogramVec{desc: desc{name, help, labels}, buckets: buckets, values: map[string]*histogramValue{}}
+	r.register(h)
+	return h
+}
+
+// Observe 記錄一次觀測值。
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	k := h.key(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	hv, ok := h.values[k]
+	if !ok {
+		hv = &histogramValue{labels: append([]string(nil), labelValues...), counts: make([]uint64, len(h.buckets))}
+		h.values[k] = hv
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		hv.counts[i]++
+	}
+	hv.count++
+	hv.sum += v
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.header(w, "histogram")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, k := range sortedKeys(h.values) {
+		hv := h.values[k]
+		var cum uint64
+		for i, ub := range h.buckets {
+			cum += hv.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.n, h.labelPairs(hv.labels, "le", formatFloat(ub)), cum)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.n, h.labelPairs(hv.labels, "le", "+Inf"), hv.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.n, h.labelPairs(hv.labels), formatFloat(hv.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.n, h.labelPairs(hv.labels), hv.count)
+	}
+}
+
+// GaugeFunc 為抓取時才計算的量測值（例如遊戲資源），避免在每次狀態變更時同步更新。
+type GaugeFunc struct {
+	desc
+	collect func(emit func(v float64, labelValues ...string))
+}
+
+// NewGaugeFunc 註冊 gauge；collect 於每次輸出時呼叫，透過 emit 回報各標籤值的目前數值。
+func (r *Registry) NewGaugeFunc(name, help string, labels []string, collect func(emit func(v float64, labelValues ...string))) *GaugeFunc {
+	g := &GaugeFunc{desc: desc{name, help, labels}, collect: collect}
+	r.register(g)
+	return g
+}
+
+func (g *GaugeFunc) write(w *bufio.Writer) {
+	samples := gaugeSamples{}
+	g.collect(func(v float64, labelValues ...string) { samples.set(g.desc, v, labelValues) })
+	samples.write(w, g.desc)
+}
+
+// GaugeSet 為一組共用同一次計算的 gauge：每次輸出只呼叫一次 collect，
+// 由同一份狀態推得的數值彼此一致，也不必為每個 gauge 重複取得狀態。
+type GaugeSet struct {
+	gauges  []desc // 依名稱排序
+	collect func(emit func(name string, v float64, labelValues ...string))
+}
+
+// GaugeDesc 描述 GaugeSet 中的一個 gauge。
+type GaugeDesc struct {
+	Name, Help string
+	Labels     []string
+}
+
+// NewGaugeSet 註冊一組 gauge；collect 於每次輸出時呼叫一次，透過 emit 以名稱回報各 gauge 的數值。
+// emit 未註冊的名稱時 panic（屬程式錯誤）。
+func (r *Registry) NewGaugeSet(gauges []GaugeDesc, collect func(emit func(name string, v float64, labelValues ...string))) *GaugeSet {
+	if len(gauges) == 0 {
+		panic("metrics: empty gauge set")
+	}
+	s := &GaugeSet{collect: collect}
+	for _, g := range gauges {
+		s.gauges = append(s.gauges, desc{g.Name, g.Help, g.Labels})
+	}
+	sort.Slice(s.gauges, func(i, j int) bool { return s.gauges[i].n < s.gauges[j].n })
+	r.register(s)
+	return s
+}
+
+func (s *GaugeSet) name() string { return s.gauges[0].n }
+
+func (s *GaugeSet) names() []string {
+	out := make([]string, len(s.gauges))
+	for i, g := range s.gauges {
+		out[i] = g.n
+	}
+	return out
+}
+
+func (s *GaugeSet) write(w *bufio.Writer) {
+	samples := make(map[string]gaugeSamples, len(s.gauges))
+	index := make(map[string]desc, len(s.gauges))
+	for _, g := range s.gauges {
+		samples[g.n] = gaugeSamples{}
+		index[g.n] = g
+	}
+	s.collect(func(name string, v float64, labelValues ...string) {
+		g, ok := index[name]
+		if !ok {
+			panic("metrics: unknown gauge " + name + " in set")
+		}
+		samples[name].set(g, v, labelValues)
+	})
+	for _, g := range s.gauges {
+		samples[g.n].write(w, g)
+	}
+}
+
+type gaugeSample struct {
+	labels []string
+	v      float64
+}
+
+// gaugeSamples 以標籤值為鍵收集一個 gauge 的數值，輸出時依鍵排序。
+type gaugeSamples map[string]gaugeSample
+
+func (m gaugeSamples) set(d desc, v float64, labelValues []string) {
+	m[d.key(labelValues)] = gaugeSample{append([]string(nil), labelValues...), v}
+}
+
+func (m gaugeSamples) write(w *bufio.Writer, d desc) {
+	d.header(w, "gauge")
+	for _, k := range sortedKeys(m) {
+		s := m[k]
+		fmt.Fprintf(w, "%s%s %s\n", d.n, d.labelPairs(s.labels), formatFloat(s.v))
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+// escapeLabel 依文字格式規則跳脫標籤值中的反斜線、雙引號與換行。
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+func escapeHelp(s string) string { return helpEscaper.Replace(s) }
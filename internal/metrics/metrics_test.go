@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistry_TextFormat(t *testing.T) {
+	reg := NewRegistry()
+	c := reg.NewCounter("http_requests_total", "Requests.", "route", "status")
+	c.Inc("/a", "200")
+	c.Inc("/a", "200")
+	c.Inc(`/b"\`, "500")
+	h := reg.NewHistogram("latency_seconds", "Latency.", []float64{0.1, 1}, "op")
+	h.Observe(0.05, "save")
+	h.Observe(0.1, "save")
+	h.Observe(3, "save")
+	reg.NewGaugeFunc("servers", "Servers owned.", nil, func(emit func(float64, ...string)) { emit(2) })
+
+	var b strings.Builder
+	if err := reg.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP http_requests_total Requests.
+# TYPE http_requests_total counter
+http_requests_total{route="/a",status="200"} 2
+http_requests_total{route="/b\"\\",status="500"} 1
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{op="save",le="0.1"} 2
+latency_seconds_bucket{op="save",le="1"} 2
+latency_seconds_bucket{op="save",le="+Inf"} 3
+latency_seconds_sum{op="save"} 3.15
+latency_seconds_count{op="save"} 3
+# HELP servers Servers owned.
+# TYPE servers gauge
+servers 2
+`
+	if b.String() != want {
+		t.Fatalf("unexpected output:\n%s", b.String())
+	}
+}
+
+func TestRegistry_DuplicateAndLabelMismatchPanic(t *testing.T) {
+	reg := NewRegistry()
+	c := reg.NewCounter("x_total", "x", "a")
+	for name, f := range map[string]func(){
+		"duplicate":      func() { reg.NewCounter("x_total", "x") },
+		"label mismatch": func() { c.Inc() },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s should panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestRegistry_GaugeSetCollectsOncePerWrite(t *testing.T) {
+	reg := NewRegistry()
+	calls := 0
+	reg.NewGaugeSet([]GaugeDesc{
+		{Name: "b_level", Help: "Level.", Labels: []string{"lang"}},
+		{Name: "a_total", Help: "Total."},
+	}, func(emit func(string, float64, ...string)) {
+		calls++
+		emit("a_total", 3)
+		emit("b_level", 1, "go")
+	})
+
+	var b strings.Builder
+	if err := reg.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP a_total Total.
+# TYPE a_total gauge
+a_total 3
+# HELP b_level Level.
+# TYPE b_level gauge
+b_level{lang="go"} 1
+`
+	if calls != 1 || b.String() != want {
+		t.Fatalf("collect calls=%d, output:\n%s", calls, b.String())
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("a gauge name already in a set should panic")
+		}
+	}()
+	reg.NewCounter("b_level", "dup")
+}
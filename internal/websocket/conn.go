@@ -0,0 +1,273 @@
+// Package websocket 以標準函式庫實作 RFC 6455 的最小子集（握手、分框、遮罩、ping/pong、關閉），
+// 供伺服器 adapter 與 Ebiten 客戶端共用，不引入外部相依。
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// 訊息類型（opcode）。
+const (
+	OpContinuation = 0x0
+	OpText         = 0x1
+	OpBinary       = 0x2
+	OpClose        = 0x8
+	OpPing         = 0x9
+	OpPong         = 0xA
+)
+
+// 關閉狀態碼（RFC 6455 §7.4.1）。
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseInvalidPayload  = 1007
+	CloseMessageTooBig   = 1009
+	CloseInternalError   = 1011
+	closeNoStatusPresent = 1005
+)
+
+// MaxMessageSize 為單一訊息（含分片合併後）的上限。
+const MaxMessageSize = 1 << 20
+
+// magicGUID 用於計算 Sec-WebSocket-Accept。
+const magicGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+var (
+	errProtocol = errors.New("websocket: protocol error")
+	errTooBig   = errors.New("websocket: message too big")
+)
+
+// CloseError 表示對方送出關閉框架（或連線因協定錯誤關閉）。
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket: closed (%d %s)", e.Code, e.Reason)
+}
+
+// Conn 為一條 WebSocket 連線。讀取需由單一 goroutine 進行；寫入可併發（內部串行化）。
+type Conn struct {
+	nc     net.Conn
+	br     *bufio.Reader
+	client bool // 客戶端送出的框架必須遮罩
+
+	wmu    sync.Mutex
+	closed bool
+}
+
+func newConn(nc net.Conn, br *bufio.Reader, client bool) *Conn {
+	if br == nil {
+		br = bufio.NewReader(nc)
+	}
+	return &Conn{nc: nc, br: br, client: client}
+}
+
+// ReadMessage 讀取下一則資料訊息（text/binary），自動合併分片、回應 ping；
+// 收到關閉框架時回覆關閉並回傳 *CloseError。
+func (c *Conn) ReadMessage() (op int, data []byte, err error) {
+	var (
+		msgOp int
+		buf   []byte
+	)
+	for {
+		fin, fop, payload, err := c.readFrame()
+		if err != nil {
+			if errors.Is(err, errProtocol) {
+				c.closeWith(CloseProtocolError, err.Error())
+			} else if errors.Is(err, errTooBig) {
+				c.closeWith(CloseMessageTooBig, err.Error())
+			}
+			return 0, nil, err
+		}
+		switch fop {
+		case OpPing:
+			if err := c.WriteMessage(OpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case OpPong:
+			continue
+		case OpClose:
+			ce := &CloseError{Code: closeNoStatusPresent}
+			if len(payload) >= 2 {
+				ce.Code = int(binary.BigEndian.Uint16(payload))
+				ce.Reason = string(payload[2:])
+			}
+			c.closeWith(CloseNormal, "")
+			return 0, nil, ce
+		case OpText, OpBinary:
+			if msgOp != 0 {
+				c.closeWith(CloseProtocolError, "new message inside fragmented message")
+				return 0, nil, errProtocol
+			}
+			msgOp = fop
+		case OpContinuation:
+			if msgOp == 0 {
+				c.closeWith(CloseProtocolError, "unexpected continuation frame")
+				return 0, nil, errProtocol
+			}
+		default:
+			c.closeWith(CloseProtocolError, "unknown opcode")
+			return 0, nil, errProtocol
+		}
+		if len(buf)+len(payload) > MaxMessageSize {
+			c.closeWith(CloseMessageTooBig, "message too big")
+			return 0, nil, errTooBig
+		}
+		buf = append(buf, payload...)
+		if fin {
+			if msgOp == OpText && !utf8.Valid(buf) {
+				c.closeWith(CloseInvalidPayload, "invalid UTF-8")
+				return 0, nil, errProtocol
+			}
+			return msgOp, buf, nil
+		}
+	}
+}
+
+// readFrame 讀取單一框架並解除遮罩；伺服器端要求客戶端框架必須遮罩。
+func (c *Conn) readFrame() (fin bool, op int, payload []byte, err error) {
+	var h [2]byte
+	if _, err = io.ReadFull(c.br, h[:]); err != nil {
+		return
+	}
+	fin = h[0]&0x80 != 0
+	if h[0]&0x70 != 0 {
+		return false, 0, nil, fmt.Errorf("%w: reserved bits set", errProtocol)
+	}
+	op = int(h[0] & 0x0F)
+	masked := h[1]&0x80 != 0
+	if masked == c.client {
+		return false, 0, nil, fmt.Errorf("%w: unexpected masking", errProtocol)
+	}
+	n := uint64(h[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if op >= OpClose && (n > 125 || !fin) {
+		return false, 0, nil, fmt.Errorf("%w: invalid control frame", errProtocol)
+	}
+	if n > MaxMessageSize {
+		return false, 0, nil, errTooBig
+	}
+	var key [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.br, key[:]); err != nil {
+			return
+		}
+	}
+	payload = make([]byte, n)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return
+	}
+	if masked {
+		maskBytes(key, payload)
+	}
+	return fin, op, payload, nil
+}
+
+// WriteMessage 以單一框架送出訊息（客戶端自動遮罩）。
+func (c *Conn) WriteMessage(op int, data []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closed {
+		return net.ErrClosed
+	}
+	return c.writeFrame(op, data)
+}
+
+func (c *Conn) writeFrame(op int, data []byte) error {
+	frame := make([]byte, 0, len(data)+14)
+	frame = append(frame, 0x80|byte(op))
+	maskBit := byte(0)
+	if c.client {
+		maskBit = 0x80
+	}
+	switch n := len(data); {
+	case n <= 125:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, maskBit|126, byte(n>>8), byte(n))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	if c.client {
+		var key [4]byte
+		if _, err := rand.Read(key[:]); err != nil {
+			return err
+		}
+		frame = append(frame, key[:]...)
+		start := len(frame)
+		frame = append(frame, data...)
+		maskBytes(key, frame[start:])
+	} else {
+		frame = append(frame, data...)
+	}
+	_, err := c.nc.Write(frame)
+	return err
+}
+
+// SetReadDeadline 設定讀取期限（用於閒置偵測）；零值表示不限。
+func (c *Conn) SetReadDeadline(t time.Time) error { return c.nc.SetReadDeadline(t) }
+
+// Close 送出正常關閉框架並關閉底層連線。
+func (c *Conn) Close() error {
+	c.closeWith(CloseNormal, "")
+	return nil
+}
+
+// CloseWith 以指定狀態碼與原因關閉連線。
+func (c *Conn) CloseWith(code int, reason string) { c.closeWith(code, reason) }
+
+func (c *Conn) closeWith(code int, reason string) {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	if len(reason) > 123 {
+		reason = reason[:123]
+	}
+	_ = c.writeFrame(OpClose, append(payload, reason...))
+	_ = c.nc.Close()
+}
+
+func maskBytes(key [4]byte, b []byte) {
+	for i := range b {
+		b[i] ^= key[i%4]
+	}
+}
+
+// acceptKey 計算握手回應的 Sec-WebSocket-Accept。
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + magicGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
@@ -0,0 +1,171 @@
+package websocket
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAcceptKey_RFCExample(t *testing.T) {
+	// RFC 6455 §1.3 的範例
+	if got := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("acceptKey = %q", got)
+	}
+}
+
+func echoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := Upgrade(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer c.Close()
+		for {
+			op, data, err := c.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := c.WriteMessage(op, data); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func dial(t *testing.T, srv *httptest.Server) *Conn {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	c, err := Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestEcho_SmallAndLargeMessages(t *testing.T) {
+	c := dial(t, echoServer(t))
+	for _, n := range []int{0, 10, 125, 126, 70000} {
+		msg := bytes.Repeat([]byte("a"), n)
+		if err := c.WriteMessage(OpText, msg); err != nil {
+			t.Fatal(err)
+		}
+		op, got, err := c.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op != OpText || !bytes.Equal(got, msg) {
+			t.Fatalf("size %d: echo mismatch (op=%d len=%d)", n, op, len(got))
+		}
+	}
+}
+
+func TestReadMessage_FragmentsAndPing(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	sc := newConn(server, nil, false)
+	go func() {
+		// 客戶端端：分兩片送出文字訊息，中間夾一個 ping
+		// （net.Pipe 為同步管線，須先讀回 pong 才能送出下一片）
+		cc := newConn(client, nil, true)
+		writeRaw(t, cc, false, OpText, []byte("hel"))
+		writeRaw(t, cc, true, OpPing, []byte("p"))
+		if _, op, payload, err := cc.readFrame(); err != nil || op != OpPong || string(payload) != "p" {
+			t.Errorf("pong = %d %q %v", op, payload, err)
+		}
+		writeRaw(t, cc, true, OpContinuation, []byte("lo"))
+	}()
+	op, data, err := sc.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op != OpText || string(data) != "hello" {
+		t.Fatalf("got %d %q", op, data)
+	}
+}
+
+// writeRaw 直接寫出單一框架（可指定 FIN 以測試分片）。
+func writeRaw(t *testing.T, c *Conn, fin bool, op int, data []byte) {
+	t.Helper()
+	var b bytes.Buffer
+	h := byte(op)
+	if fin {
+		h |= 0x80
+	}
+	b.WriteByte(h)
+	b.WriteByte(0x80 | byte(len(data)))
+	key := [4]byte{1, 2, 3, 4}
+	b.Write(key[:])
+	masked := append([]byte(nil), data...)
+	maskBytes(key, masked)
+	b.Write(masked)
+	if _, err := c.nc.Write(b.Bytes()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReadMessage_UnmaskedClientFrameIsProtocolError(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	sc := newConn(server, nil, false)
+	go func() {
+		client.Write([]byte{0x81, 0x01, 'x'}) // 未遮罩
+		// 讀取伺服器回覆的關閉框架
+		buf := make([]byte, 64)
+		n, _ := client.Read(buf)
+		if n < 4 || buf[0] != 0x88 || binary.BigEndian.Uint16(buf[2:4]) != CloseProtocolError {
+			t.Errorf("close frame = %x", buf[:n])
+		}
+	}()
+	if _, _, err := sc.ReadMessage(); !errors.Is(err, errProtocol) {
+		t.Fatalf("err = %v, want protocol error", err)
+	}
+}
+
+func TestClose_PeerReceivesCloseError(t *testing.T) {
+	c := dial(t, echoServer(t))
+	if err := c.WriteMessage(OpClose, binary.BigEndian.AppendUint16(nil, CloseGoingAway)); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := c.ReadMessage()
+	var ce *CloseError
+	if !errors.As(err, &ce) || ce.Code != CloseNormal {
+		t.Fatalf("err = %v, want close 1000 echo", err)
+	}
+}
+
+func TestUpgrade_RejectsPlainRequest(t *testing.T) {
+	srv := echoServer(t)
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+}
+
+func TestDial_RejectedHandshakeKeepsBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	}))
+	defer srv.Close()
+	_, err := Dial(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	var he *HandshakeError
+	if !errors.As(err, &he) || he.StatusCode != http.StatusForbidden || !strings.Contains(string(he.Body), "nope") {
+		t.Fatalf("err = %v", err)
+	}
+}